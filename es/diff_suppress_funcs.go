@@ -2,16 +2,17 @@ package es
 
 import (
 	"encoding/json"
-	"fmt"
+	"log"
 	"reflect"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/olivere/elastic/v7"
-	log "github.com/sirupsen/logrus"
 )
 
+// The diff suppress functions not get context to use tflog, so they log with standard logger that Terraform capture
+
 // diffSuppressIndexTemplateLegacy permit to compare template in current state vs from API
 func diffSuppressIndexTemplateLegacy(k, old, new string, d *schema.ResourceData) bool {
 
@@ -19,13 +20,11 @@ func diffSuppressIndexTemplateLegacy(k, old, new string, d *schema.ResourceData)
 	no := &elastic.IndicesGetTemplateResponse{}
 
 	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		fmt.Printf("[ERR] Error when converting to IndicesGetComponentTemplate: %s", err.Error())
-		log.Errorf("Error when converting to IndicesGetComponentTemplate: %s", err.Error())
+		log.Printf("[DEBUG] Error when converting old object to IndicesGetTemplateResponse: %s", err.Error())
 		return false
 	}
 	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		fmt.Printf("[ERR] Error when converting to IndicesGetComponentTemplate: %s", err.Error())
-		log.Errorf("Error when converting to IndicesGetComponentTemplate: %s", err.Error())
+		log.Printf("[DEBUG] Error when converting new object to IndicesGetTemplateResponse: %s", err.Error())
 		return false
 	}
 
@@ -67,9 +66,11 @@ func diffSuppressIndexTemplateLegacy(k, old, new string, d *schema.ResourceData)
 func suppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
 	var oldObj, newObj interface{}
 	if err := json.Unmarshal([]byte(old), &oldObj); err != nil {
+		log.Printf("[DEBUG] Error when converting old object to JSON: %s", err.Error())
 		return false
 	}
 	if err := json.Unmarshal([]byte(new), &newObj); err != nil {
+		log.Printf("[DEBUG] Error when converting new object to JSON: %s", err.Error())
 		return false
 	}
 	return reflect.DeepEqual(oldObj, newObj)
@@ -81,9 +82,11 @@ func suppressLicense(k, old, new string, d *schema.ResourceData) bool {
 	oldObj := &LicenseSpec{}
 	newObjTemp := make(License)
 	if err := json.Unmarshal([]byte(old), oldObj); err != nil {
+		log.Printf("[DEBUG] Error when converting old object to License: %s", err.Error())
 		return false
 	}
 	if err := json.Unmarshal([]byte(new), &newObjTemp); err != nil {
+		log.Printf("[DEBUG] Error when converting new object to License: %s", err.Error())
 		return false
 	}
 	newObj := newObjTemp["license"]
@@ -91,7 +94,7 @@ func suppressLicense(k, old, new string, d *schema.ResourceData) bool {
 	newObj.Signature = ""
 	oldObj.Signature = ""
 
-	log.Debugf("Old: %s\nNew: %s", oldObj, newObj)
+	log.Printf("[DEBUG] Old: %s\nNew: %s", oldObj, newObj)
 
 	return reflect.DeepEqual(oldObj, newObj)
 }
@@ -148,13 +151,11 @@ func diffSuppressIndexComponentTemplate(k, old, new string, d *schema.ResourceDa
 	no := &elastic.IndicesGetComponentTemplate{}
 
 	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		fmt.Printf("[ERR] Error when converting to IndicesGetComponentTemplate: %s", err.Error())
-		log.Errorf("Error when converting to IndicesGetComponentTemplate: %s", err.Error())
+		log.Printf("[DEBUG] Error when converting old object to IndicesGetComponentTemplate: %s", err.Error())
 		return false
 	}
 	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		fmt.Printf("[ERR] Error when converting to IndicesGetComponentTemplate: %s", err.Error())
-		log.Errorf("Error when converting to IndicesGetComponentTemplate: %s", err.Error())
+		log.Printf("[DEBUG] Error when converting new object to IndicesGetComponentTemplate: %s", err.Error())
 		return false
 	}
 
@@ -203,13 +204,11 @@ func diffSuppressIndexTemplate(k, old, new string, d *schema.ResourceData) bool
 	no := &elastic.IndicesGetIndexTemplate{}
 
 	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		fmt.Printf("[ERR] Error when converting to IndicesGetIndexTemplate on old object: %s", err.Error())
-		log.Errorf("Error when converting to IndicesGetIndexTemplate on old object: %s\n%s", err.Error(), old)
+		log.Printf("[DEBUG] Error when converting old object to IndicesGetIndexTemplate: %s", err.Error())
 		return false
 	}
 	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		fmt.Printf("[ERR] Error when converting to IndicesGetIndexTemplate on new object: %s", err.Error())
-		log.Errorf("Error when converting to IndicesGetIndexTemplate on new object: %s\n%s", err.Error(), new)
+		log.Printf("[DEBUG] Error when converting new object to IndicesGetIndexTemplate: %s", err.Error())
 		return false
 	}
 
@@ -251,3 +250,73 @@ func diffSuppressIndexTemplate(k, old, new string, d *schema.ResourceData) bool
 
 	return reflect.DeepEqual(no, oo)
 }
+
+// diffSuppressIndexSettings permit to compare index settings in current state vs from API
+// Settings are compared as flat settings, like Elasticsearch return them
+func diffSuppressIndexSettings(k, old, new string, d *schema.ResourceData) bool {
+	oo, err := flattenIndexSettingsJSON(old)
+	if err != nil {
+		log.Printf("[DEBUG] Error when converting old object to index settings: %s", err.Error())
+		return false
+	}
+	no, err := flattenIndexSettingsJSON(new)
+	if err != nil {
+		log.Printf("[DEBUG] Error when converting new object to index settings: %s", err.Error())
+		return false
+	}
+
+	return reflect.DeepEqual(oo, no)
+}
+
+// diffSuppressIndexDotProperties permit to compare index mappings or aliases in current state vs from API
+func diffSuppressIndexDotProperties(k, old, new string, d *schema.ResourceData) bool {
+	oo := make(map[string]interface{})
+	no := make(map[string]interface{})
+
+	if old != "" {
+		if err := json.Unmarshal([]byte(old), &oo); err != nil {
+			log.Printf("[DEBUG] Error when converting old object to JSON: %s", err.Error())
+			return false
+		}
+	}
+	if new != "" {
+		if err := json.Unmarshal([]byte(new), &no); err != nil {
+			log.Printf("[DEBUG] Error when converting new object to JSON: %s", err.Error())
+			return false
+		}
+	}
+
+	// force undot properties to compare the same think
+	return reflect.DeepEqual(parseAllDotProperties(oo), parseAllDotProperties(no))
+}
+
+// diffSuppressClusterSettings permit to compare cluster settings in current state vs from API
+// Settings are compared as flat settings, like Elasticsearch return them
+func diffSuppressClusterSettings(k, old, new string, d *schema.ResourceData) bool {
+	oo, err := flattenClusterSettingsJSON(old)
+	if err != nil {
+		log.Printf("[DEBUG] Error when converting old object to cluster settings: %s", err.Error())
+		return false
+	}
+	no, err := flattenClusterSettingsJSON(new)
+	if err != nil {
+		log.Printf("[DEBUG] Error when converting new object to cluster settings: %s", err.Error())
+		return false
+	}
+
+	return reflect.DeepEqual(oo, no)
+}
+
+// suppressEquivalentTransformSource permit to compare transform source when the index is set as string or as list
+func suppressEquivalentTransformSource(k, old, new string, d *schema.ResourceData) bool {
+	var oldObj, newObj interface{}
+	if err := json.Unmarshal([]byte(old), &oldObj); err != nil {
+		log.Printf("[DEBUG] Error when converting old object to JSON: %s", err.Error())
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newObj); err != nil {
+		log.Printf("[DEBUG] Error when converting new object to JSON: %s", err.Error())
+		return false
+	}
+	return reflect.DeepEqual(normalizeTransformSource(oldObj), normalizeTransformSource(newObj))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -14,10 +15,11 @@ import (
 
 	elastic "github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // Provider permiit to init the terraform provider
@@ -26,10 +28,16 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"urls": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_URLS", nil),
 				Description: "Elasticsearch URLs",
 			},
+			"cloud_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_CLOUD_ID", nil),
+				Description: "Elastic Cloud ID, it can be used instead of urls",
+			},
 			"username": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -42,18 +50,66 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_PASSWORD", nil),
 				Description: "Password to use to connect to elasticsearch using basic auth",
 			},
+			"api_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_API_KEY", nil),
+				Description: "API key to use to connect to elasticsearch, either as `id:api_key` or already base64 encoded",
+			},
+			"bearer_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_BEARER_TOKEN", nil),
+				Description: "Bearer token to use to connect to elasticsearch, like service account token or OAuth2 token",
+			},
 			"cacert_file": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "",
 				Description: "A Custom CA certificate",
 			},
+			"client_cert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "A client certificate to use to connect to elasticsearch using PKI, as file path or PEM content",
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Default:     "",
+				Description: "The private key of the client certificate, as file path or PEM content",
+			},
 			"insecure": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
 				Description: "Disable SSL verification of API calls",
 			},
+			"headers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Custom HTTP headers to add on each API call",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_PROXY_URL", nil),
+				Description: "Proxy URL to use to connect to elasticsearch. Default to use proxy from HTTP_PROXY / HTTPS_PROXY environment variables",
+			},
+			"http_trace": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Log each API call with request and response, secrets are redacted",
+			},
 			"retry": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -66,60 +122,136 @@ func Provider() *schema.Provider {
 				Default:     10,
 				Description: "Wait time in second before retry connexion",
 			},
+			"max_retries": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     5,
+				Description: "Number time it retry each idempotent API call that failed with transient error, with exponential backoff. Set 0 to disable retry",
+			},
+			"retry_on_status": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "List of HTTP status code that are retried on each API call. Default to 429, 502, 503 and 504",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"elasticsearch_index_lifecycle_policy":    resourceElasticsearchIndexLifecyclePolicy(),
-			"elasticsearch_index_template_legacy":     resourceElasticsearchIndexTemplateLegacy(),
-			"elasticsearch_index_template":            resourceElasticsearchIndexTemplate(),
-			"elasticsearch_index_component_template":  resourceElasticsearchIndexComponentTemplate(),
-			"elasticsearch_role":                      resourceElasticsearchSecurityRole(),
-			"elasticsearch_role_mapping":              resourceElasticsearchSecurityRoleMapping(),
-			"elasticsearch_user":                      resourceElasticsearchSecurityUser(),
-			"elasticsearch_license":                   resourceElasticsearchLicense(),
-			"elasticsearch_snapshot_repository":       resourceElasticsearchSnapshotRepository(),
-			"elasticsearch_snapshot_lifecycle_policy": resourceElasticsearchSnapshotLifecyclePolicy(),
-			"elasticsearch_watcher":                   resourceElasticsearchWatcher(),
+			"elasticsearch_index":                     withSupportedVersions(resourceElasticsearchIndex(), "7.0.0", "9.0.0"),
+			"elasticsearch_index_alias":               withSupportedVersions(resourceElasticsearchIndexAlias(), "7.0.0", "9.0.0"),
+			"elasticsearch_cluster_settings":          withSupportedVersions(resourceElasticsearchClusterSettings(), "7.0.0", "9.0.0"),
+			"elasticsearch_ccr_auto_follow_pattern":   withSupportedVersions(resourceElasticsearchCCRAutoFollowPattern(), "7.5.0", "9.0.0"),
+			"elasticsearch_ccr_follower_index":        withSupportedVersions(resourceElasticsearchCCRFollowerIndex(), "7.0.0", "9.0.0"),
+			"elasticsearch_remote_cluster":            withSupportedVersions(resourceElasticsearchRemoteCluster(), "7.0.0", "9.0.0"),
+			"elasticsearch_data_stream":               withSupportedVersions(resourceElasticsearchDataStream(), "7.9.0", "9.0.0"),
+			"elasticsearch_enrich_policy":             withSupportedVersions(resourceElasticsearchEnrichPolicy(), "7.5.0", "9.0.0"),
+			"elasticsearch_ingest_pipeline":           withSupportedVersions(resourceElasticsearchIngestPipeline(), "7.0.0", "9.0.0"),
+			"elasticsearch_index_lifecycle_policy":    withSupportedVersions(resourceElasticsearchIndexLifecyclePolicy(), "7.0.0", "9.0.0"),
+			"elasticsearch_index_template_legacy":     withSupportedVersions(resourceElasticsearchIndexTemplateLegacy(), "7.0.0", "9.0.0"),
+			"elasticsearch_index_template":            withSupportedVersions(resourceElasticsearchIndexTemplate(), "7.8.0", "9.0.0"),
+			"elasticsearch_index_component_template":  withSupportedVersions(resourceElasticsearchIndexComponentTemplate(), "7.8.0", "9.0.0"),
+			"elasticsearch_ml_job":                    withSupportedVersions(resourceElasticsearchMLJob(), "7.0.0", "9.0.0"),
+			"elasticsearch_ml_datafeed":               withSupportedVersions(resourceElasticsearchMLDatafeed(), "7.0.0", "9.0.0"),
+			"elasticsearch_api_key":                   withSupportedVersions(resourceElasticsearchSecurityAPIKey(), "7.0.0", "9.0.0"),
+			"elasticsearch_application_privileges":    withSupportedVersions(resourceElasticsearchSecurityApplicationPrivileges(), "7.0.0", "9.0.0"),
+			"elasticsearch_role":                      withSupportedVersions(resourceElasticsearchSecurityRole(), "7.0.0", "9.0.0"),
+			"elasticsearch_role_mapping":              withSupportedVersions(resourceElasticsearchSecurityRoleMapping(), "7.0.0", "9.0.0"),
+			"elasticsearch_builtin_user_password":     withSupportedVersions(resourceElasticsearchSecurityBuiltinUserPassword(), "7.0.0", "9.0.0"),
+			"elasticsearch_user":                      withSupportedVersions(resourceElasticsearchSecurityUser(), "7.0.0", "9.0.0"),
+			"elasticsearch_license":                   withSupportedVersions(resourceElasticsearchLicense(), "7.0.0", "9.0.0"),
+			"elasticsearch_service_account_token":     withSupportedVersions(resourceElasticsearchSecurityServiceAccountToken(), "7.13.0", "9.0.0"),
+			"elasticsearch_rollup_job":                withSupportedVersions(resourceElasticsearchRollupJob(), "7.0.0", "9.0.0"),
+			"elasticsearch_transform":                 withSupportedVersions(resourceElasticsearchTransform(), "7.5.0", "9.0.0"),
+			"elasticsearch_snapshot_repository":       withSupportedVersions(resourceElasticsearchSnapshotRepository(), "7.0.0", "9.0.0"),
+			"elasticsearch_snapshot_lifecycle_policy": withSupportedVersions(resourceElasticsearchSnapshotLifecyclePolicy(), "7.4.0", "9.0.0"),
+			"elasticsearch_watcher":                   withSupportedVersions(resourceElasticsearchWatcher(), "7.0.0", "9.0.0"),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
 }
 
 // providerConfigure permit to initialize the rest client to access on Elasticsearch API
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 
 	var (
 		data map[string]interface{}
 	)
 
-	URLs := strings.Split(d.Get("urls").(string), ",")
+	rawURLs := d.Get("urls").(string)
+	cloudID := d.Get("cloud_id").(string)
 	insecure := d.Get("insecure").(bool)
 	cacertFile := d.Get("cacert_file").(string)
+	clientCert := d.Get("client_cert").(string)
+	clientKey := d.Get("client_key").(string)
 	username := d.Get("username").(string)
 	password := d.Get("password").(string)
+	apiKey := d.Get("api_key").(string)
+	bearerToken := d.Get("bearer_token").(string)
+	headers := convertMapInterfaceToMapString(d.Get("headers").(map[string]interface{}))
+	proxyURL := d.Get("proxy_url").(string)
+	httpTrace := d.Get("http_trace").(bool)
 	retry := d.Get("retry").(int)
 	waitBeforeRetry := d.Get("wait_before_retry").(int)
+	maxRetries := d.Get("max_retries").(int)
+	retryOnStatus := convertArrayInterfaceToArrayInt(d.Get("retry_on_status").([]interface{}))
 	transport := &http.Transport{
 		Proxy:           http.ProxyFromEnvironment,
 		TLSClientConfig: &tls.Config{},
 	}
-	// Checks is valid URLs
-	for _, rawURL := range URLs {
-		_, err := url.Parse(rawURL)
-		if err != nil {
-			return nil, err
+	// Intialise connexion
+	cfg := elastic.Config{}
+	if rawURLs != "" && cloudID != "" {
+		return nil, diag.Errorf("You can't use urls and cloud_id at the same time")
+	}
+	if rawURLs == "" && cloudID == "" {
+		return nil, diag.Errorf("You need to set urls or cloud_id")
+	}
+	if cloudID != "" {
+		cfg.CloudID = cloudID
+	} else {
+		URLs := strings.Split(rawURLs, ",")
+		// Checks is valid URLs
+		for _, rawURL := range URLs {
+			_, err := url.Parse(rawURL)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
 		}
+		cfg.Addresses = URLs
 	}
-
-	// Intialise connexion
-	cfg := elastic.Config{
-		Addresses: URLs,
+	// Check that only one authentication method is used
+	if apiKey != "" && bearerToken != "" {
+		return nil, diag.Errorf("You can't use api_key and bearer_token at the same time")
+	}
+	if (apiKey != "" || bearerToken != "") && (username != "" || password != "") {
+		return nil, diag.Errorf("You can't use api_key or bearer_token with username / password basic auth")
 	}
 	if username != "" && password != "" {
 		cfg.Username = username
 		cfg.Password = password
 	}
+	if apiKey != "" {
+		cfg.APIKey = encodeAPIKey(apiKey)
+	}
+	if bearerToken != "" {
+		cfg.ServiceToken = bearerToken
+	}
+	if len(headers) > 0 {
+		cfg.Header = http.Header{}
+		for key, value := range headers {
+			cfg.Header.Set(key, value)
+		}
+	}
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, diag.FromErr(errors.Wrap(err, "Error when parse proxy_url"))
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
 	if insecure == true {
 		transport.TLSClientConfig.InsecureSkipVerify = true
 	}
@@ -131,46 +263,125 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		caCertPool.AppendCertsFromPEM([]byte(caCert))
 		transport.TLSClientConfig.RootCAs = caCertPool
 	}
+	// If a client certificate has been specified, use it for PKI authentication
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, diag.Errorf("You need to set client_cert and client_key together")
+		}
+		certPEM, _, err := read(clientCert)
+		if err != nil {
+			return nil, diag.FromErr(errors.Wrap(err, "Error when read client_cert"))
+		}
+		keyPEM, _, err := read(clientKey)
+		if err != nil {
+			return nil, diag.FromErr(errors.Wrap(err, "Error when read client_key"))
+		}
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			return nil, diag.FromErr(errors.Wrap(err, "Error when load client certificate, client_cert and client_key must match"))
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
 	cfg.Transport = transport
+	setRetryPolicy(&cfg, maxRetries, retryOnStatus)
+	if httpTrace {
+		cfg.Logger = &traceLogger{}
+	}
 	client, err := elastic.NewClient(cfg)
 	if err != nil {
-		return nil, err
+		return nil, diag.FromErr(err)
 	}
 
 	// Test connexion and check elastic version to use the right Version
+	// The connexion is already retried here, so the retry on API call is disabled
 	nbFailed := 0
 	isOnline := false
 	var res *esapi.Response
 	for isOnline == false {
 		res, err = client.API.Info(
-			client.API.Info.WithContext(context.Background()),
+			client.API.Info.WithContext(withoutRetry(ctx)),
 		)
 		if err == nil && res.IsError() == false {
 			isOnline = true
 		} else {
+			if err == nil {
+				// Bad credentials will not be fixed by waiting
+				if res.StatusCode == 401 || res.StatusCode == 403 {
+					defer res.Body.Close()
+					return nil, diag.Errorf("Error when authenticate on Elasticsearch: %s", res.String())
+				}
+				err = errors.Errorf("Error when get info about Elasticsearch: %s", res.String())
+				res.Body.Close()
+			}
 			if nbFailed == retry {
-				return nil, err
+				return nil, diag.FromErr(err)
 			}
 			nbFailed++
-			time.Sleep(time.Duration(waitBeforeRetry) * time.Second)
+			tflog.Warn(ctx, "Elasticsearch not yet available, wait before retry", "error", err.Error(), "attempt", nbFailed)
+			select {
+			case <-ctx.Done():
+				return nil, diag.FromErr(ctx.Err())
+			case <-time.After(time.Duration(waitBeforeRetry) * time.Second):
+			}
 		}
 	}
 
 	defer res.Body.Close()
 	if res.IsError() {
-		return nil, errors.Errorf("Error when get info about Elasticsearch client: %s", res.String())
+		return nil, diag.Errorf("Error when get info about Elasticsearch client: %s", res.String())
 	}
 	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
-		return nil, err
+		return nil, diag.FromErr(err)
 	}
-	version := data["version"].(map[string]interface{})["number"].(string)
-	log.Debugf("Server: %s", version)
+	serverVersion, err := parseServerVersion(data["version"].(map[string]interface{})["number"].(string))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	tflog.Debug(ctx, "Elasticsearch version detected", "version", serverVersion.String())
+
+	// Elasticsearch 8.x need compatibility header to use 7.x API
+	if serverVersion.Segments()[0] >= 8 {
+		cfg.EnableCompatibilityMode = true
+		client, err = elastic.NewClient(cfg)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
+	// License is only used to enable features, so failed to get it is not blocking
+	licenseType, err := getLicenseType(ctx, client)
+	if err != nil {
+		tflog.Warn(ctx, "Can't get the Elasticsearch license", "error", err.Error())
+	}
+	tflog.Debug(ctx, "Elasticsearch license detected", "license", licenseType)
+
+	return &ProviderMeta{
+		client:  client,
+		version: serverVersion,
+		license: licenseType,
+		config: &ProviderConfig{
+			URLs:            cfg.Addresses,
+			CloudID:         cloudID,
+			Username:        username,
+			Insecure:        insecure,
+			CacertFile:      cacertFile,
+			ProxyURL:        proxyURL,
+			HTTPTrace:       httpTrace,
+			Retry:           retry,
+			WaitBeforeRetry: waitBeforeRetry,
+			MaxRetries:      maxRetries,
+			RetryOnStatus:   cfg.RetryOnStatus,
+		},
+	}, nil
+}
 
-	if version < "7.0.0" || version >= "8.0.0" {
-		return nil, errors.Errorf("ElasticSearch version is not 7.x (%s), you need to use the right version of elasticsearch provider", version)
+// encodeAPIKey permit to accept API key as `id:api_key` or already encoded in base64
+func encodeAPIKey(apiKey string) string {
+	if strings.Contains(apiKey, ":") {
+		return base64.StdEncoding.EncodeToString([]byte(apiKey))
 	}
 
-	return client, nil
+	return apiKey
 }
 
 // If the argument is a path, Read loads it and returns the contents,
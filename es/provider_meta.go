@@ -0,0 +1,99 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+
+	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/go-version"
+	"github.com/pkg/errors"
+)
+
+// ProviderMeta is the object shared with all resources
+type ProviderMeta struct {
+	client  *elastic.Client
+	version *version.Version
+	license string
+	config  *ProviderConfig
+}
+
+// ProviderConfig is the provider configuration, without credentials
+type ProviderConfig struct {
+	URLs            []string
+	CloudID         string
+	Username        string
+	Insecure        bool
+	CacertFile      string
+	ProxyURL        string
+	HTTPTrace       bool
+	Retry           int
+	WaitBeforeRetry int
+	MaxRetries      int
+	RetryOnStatus   []int
+}
+
+// License levels ordered from the lowest to the highest
+var licenseLevels = []string{
+	"basic",
+	"standard",
+	"gold",
+	"platinum",
+	"enterprise",
+}
+
+// hasLicense permit to check if the license detected when the provider is configured is at least the expected level
+// Trial license provides all features. Unknown level is never satisfied
+func (m *ProviderMeta) hasLicense(level string) bool {
+	current := -1
+	expected := -1
+	for i, l := range licenseLevels {
+		if l == m.license {
+			current = i
+		}
+		if l == level {
+			expected = i
+		}
+	}
+	if expected < 0 {
+		return false
+	}
+	if m.license == "trial" {
+		return true
+	}
+
+	return current >= expected
+}
+
+// getLicenseType permit to get the license type currently used by Elasticsearch
+func getLicenseType(ctx context.Context, client *elastic.Client) (string, error) {
+	res, err := client.API.License.Get(
+		client.API.License.Get.WithContext(ctx),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return "", errors.Errorf("Error when get license: %s", res.String())
+	}
+
+	license := make(License)
+	if err := json.NewDecoder(res.Body).Decode(&license); err != nil {
+		return "", err
+	}
+	if license["license"] == nil {
+		return "", nil
+	}
+
+	return license["license"].Type, nil
+}
+
+// checkLicense return error if the license detected is lower than the expected level to use a feature
+// When the license can't be detected, Elasticsearch will return the error itself
+func (m *ProviderMeta) checkLicense(level string, feature string) error {
+	if m.license == "" || m.hasLicense(level) {
+		return nil
+	}
+
+	return errors.Errorf("%s need at least %s license, current license is %s", feature, level, m.license)
+}
@@ -0,0 +1,42 @@
+package es
+
+import (
+	"testing"
+)
+
+func TestProviderMetaHasLicense(t *testing.T) {
+
+	tests := []struct {
+		current  string
+		expected string
+		result   bool
+	}{
+		{"basic", "basic", true},
+		{"basic", "platinum", false},
+		{"platinum", "gold", true},
+		{"enterprise", "platinum", true},
+		{"trial", "enterprise", true},
+		{"", "basic", false},
+		{"platinum", "unknown", false},
+		{"trial", "unknown", false},
+	}
+
+	for _, test := range tests {
+		meta := &ProviderMeta{license: test.current}
+		if meta.hasLicense(test.expected) != test.result {
+			t.Errorf("License %s with expected level %s must return %t", test.current, test.expected, test.result)
+		}
+	}
+}
+
+func TestProviderMetaCheckLicense(t *testing.T) {
+	if err := (&ProviderMeta{license: "basic"}).checkLicense("platinum", "Machine learning"); err == nil {
+		t.Errorf("Basic license must not allow platinum feature")
+	}
+	if err := (&ProviderMeta{license: "platinum"}).checkLicense("platinum", "Machine learning"); err != nil {
+		t.Errorf("Platinum license must allow platinum feature: %s", err)
+	}
+	if err := (&ProviderMeta{}).checkLicense("platinum", "Machine learning"); err != nil {
+		t.Errorf("Unknown license must be checked by Elasticsearch: %s", err)
+	}
+}
@@ -1,12 +1,17 @@
 package es
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/sirupsen/logrus"
-	easy "github.com/t-tomalak/logrus-easy-formatter"
 )
 
 var testAccProviders map[string]*schema.Provider
@@ -14,17 +19,11 @@ var testAccProvider *schema.Provider
 
 func init() {
 
-	// Init logger
-	logrus.SetFormatter(&easy.Formatter{
-		LogFormat: "[%lvl%] %msg%\n",
-	})
-	//logrus.SetLevel(logrus.DebugLevel)
-
 	// Init provider
 	testAccProvider = Provider()
-	configureFunc := testAccProvider.ConfigureFunc
-	testAccProvider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
-		return configureFunc(d)
+	configureFunc := testAccProvider.ConfigureContextFunc
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return configureFunc(ctx, d)
 	}
 	testAccProviders = map[string]*schema.Provider{
 		"elasticsearch": testAccProvider,
@@ -42,9 +41,191 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestProviderConfigureAuthentication(t *testing.T) {
+	tests := []struct {
+		config        map[string]interface{}
+		authorization string
+		failed        bool
+	}{
+		{map[string]interface{}{"api_key": "id:secret"}, "APIKey " + base64.StdEncoding.EncodeToString([]byte("id:secret")), false},
+		{map[string]interface{}{"api_key": "aWQ6c2VjcmV0"}, "APIKey aWQ6c2VjcmV0", false},
+		{map[string]interface{}{"bearer_token": "token"}, "Bearer token", false},
+		{map[string]interface{}{"username": "elastic", "password": "changeme"}, "Basic " + base64.StdEncoding.EncodeToString([]byte("elastic:changeme")), false},
+		{map[string]interface{}{"api_key": "id:secret", "bearer_token": "token"}, "", true},
+		{map[string]interface{}{"api_key": "id:secret", "username": "elastic", "password": "changeme"}, "", true},
+		{map[string]interface{}{"bearer_token": "token", "username": "elastic"}, "", true},
+	}
+
+	for _, test := range tests {
+		requests := make(chan *http.Request, 10)
+		server := newTestElasticsearch(t, requests)
+		test.config["urls"] = server.URL
+		test.config["retry"] = 0
+
+		_, err := testProviderConfigure(t, test.config)
+		server.Close()
+
+		if (err != nil) != test.failed {
+			t.Errorf("Config %+v must failed: %t, got %v", test.config, test.failed, err)
+		}
+		if !test.failed && len(requests) == 0 {
+			t.Errorf("Config %+v must call Elasticsearch", test.config)
+		}
+		for len(requests) > 0 {
+			if r := <-requests; r.Header.Get("Authorization") != test.authorization {
+				t.Errorf("Config %+v must authenticate with %s, got %s", test.config, test.authorization, r.Header.Get("Authorization"))
+			}
+		}
+	}
+}
+
+func TestProviderConfigureClientCertificate(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"client_cert": "cert"},
+		{"client_key": "key"},
+		{"client_cert": "cert", "client_key": "key"},
+	}
+
+	for _, config := range tests {
+		config["urls"] = "http://127.0.0.1:9200"
+		if _, err := testProviderConfigure(t, config); err == nil {
+			t.Errorf("Config %+v must failed", config)
+		}
+	}
+}
+
+func TestProviderConfigureURLsAndCloudID(t *testing.T) {
+	server := newTestElasticsearch(t, make(chan *http.Request, 10))
+	defer server.Close()
+
+	tests := []struct {
+		config map[string]interface{}
+		failed bool
+	}{
+		{map[string]interface{}{"urls": server.URL}, false},
+		{map[string]interface{}{"urls": server.URL, "cloud_id": "test:dGVzdA=="}, true},
+		{map[string]interface{}{}, true},
+		{map[string]interface{}{"cloud_id": "bad"}, true},
+	}
+
+	for _, test := range tests {
+		test.config["retry"] = 0
+		if _, err := testProviderConfigure(t, test.config); (err != nil) != test.failed {
+			t.Errorf("Config %+v must failed: %t, got %v", test.config, test.failed, err)
+		}
+	}
+}
+
+func TestProviderConfigureHeadersAndProxy(t *testing.T) {
+	requests := make(chan *http.Request, 10)
+	server := newTestElasticsearch(t, requests)
+	defer server.Close()
+
+	// Custom headers are sent on each API call
+	_, err := testProviderConfigure(t, map[string]interface{}{
+		"urls":    server.URL,
+		"retry":   0,
+		"headers": map[string]interface{}{"X-Custom-Header": "test"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(requests) == 0 {
+		t.Errorf("API call must be sent with custom header")
+	}
+	for len(requests) > 0 {
+		if r := <-requests; r.Header.Get("X-Custom-Header") != "test" {
+			t.Errorf("Custom header must be sent, got %+v", r.Header)
+		}
+	}
+
+	// API calls are sent to the proxy
+	_, err = testProviderConfigure(t, map[string]interface{}{
+		"urls":      "http://elasticsearch.test:9200",
+		"retry":     0,
+		"proxy_url": server.URL,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(requests) == 0 {
+		t.Errorf("API call must be sent through proxy")
+	}
+	for len(requests) > 0 {
+		if r := <-requests; r.Host != "elasticsearch.test:9200" {
+			t.Errorf("API call must be sent through proxy to elasticsearch.test:9200, got %s", r.Host)
+		}
+	}
+
+	_, err = testProviderConfigure(t, map[string]interface{}{
+		"urls":      server.URL,
+		"proxy_url": "://bad",
+	})
+	if err == nil {
+		t.Errorf("Bad proxy_url must failed")
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
-	if v := os.Getenv("ELASTICSEARCH_URLS"); v == "" {
-		t.Fatal("ELASTICSEARCH_URLS must be set for acceptance tests")
+	if os.Getenv("ELASTICSEARCH_URLS") == "" && os.Getenv("ELASTICSEARCH_CLOUD_ID") == "" {
+		t.Fatal("ELASTICSEARCH_URLS or ELASTICSEARCH_CLOUD_ID must be set for acceptance tests")
 	}
 
 }
+
+// newTestProviderMeta start fake Elasticsearch that call handler on each API call, except the product check
+func newTestProviderMeta(t *testing.T, handler http.HandlerFunc) (*ProviderMeta, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+
+		// Product check done by the client
+		if r.URL.Path == "/" {
+			w.Write([]byte(`{"version": {"number": "7.16.2", "build_flavor": "default"}, "tagline": "You Know, for Search"}`))
+			return
+		}
+
+		handler(w, r)
+	}))
+
+	client, err := elastic.NewClient(elastic.Config{
+		Addresses: []string{server.URL},
+	})
+	if err != nil {
+		server.Close()
+		t.Fatalf("err: %s", err)
+	}
+
+	return &ProviderMeta{client: client}, server
+}
+
+// testProviderConfigure call providerConfigure with the raw config, without the provider environment variables
+func testProviderConfigure(t *testing.T, raw map[string]interface{}) (interface{}, error) {
+	for _, env := range []string{"ELASTICSEARCH_URLS", "ELASTICSEARCH_CLOUD_ID", "ELASTICSEARCH_USERNAME", "ELASTICSEARCH_PASSWORD", "ELASTICSEARCH_API_KEY", "ELASTICSEARCH_BEARER_TOKEN", "ELASTICSEARCH_PROXY_URL"} {
+		t.Setenv(env, "")
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, raw)
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		return nil, errors.New(diags[0].Summary)
+	}
+
+	return meta, nil
+}
+
+// newTestElasticsearch start fake Elasticsearch that only answer to the info API. Each request is sent on requests channel
+func newTestElasticsearch(t *testing.T, requests chan *http.Request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path != "/" {
+			w.WriteHeader(404)
+			w.Write([]byte(`{}`))
+			return
+		}
+		requests <- r
+		w.Write([]byte(`{"version": {"number": "7.16.2", "build_flavor": "default"}, "tagline": "You Know, for Search"}`))
+	}))
+}
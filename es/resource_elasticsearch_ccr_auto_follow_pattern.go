@@ -0,0 +1,347 @@
+// Manage the cross cluster replication auto follow pattern in elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-apis.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// CCRAutoFollowPatterns is the auto follow patterns object returned by API
+type CCRAutoFollowPatterns struct {
+	Patterns []*CCRAutoFollowPattern `json:"patterns"`
+}
+
+// CCRAutoFollowPattern is the auto follow pattern returned by API
+type CCRAutoFollowPattern struct {
+	Name    string                    `json:"name"`
+	Pattern *CCRAutoFollowPatternSpec `json:"pattern"`
+}
+
+// CCRAutoFollowPatternSpec is the auto follow pattern specification
+type CCRAutoFollowPatternSpec struct {
+	RemoteCluster                string      `json:"remote_cluster"`
+	LeaderIndexPatterns          []string    `json:"leader_index_patterns"`
+	LeaderIndexExclusionPatterns []string    `json:"leader_index_exclusion_patterns,omitempty"`
+	FollowIndexPattern           string      `json:"follow_index_pattern,omitempty"`
+	Settings                     interface{} `json:"settings,omitempty"`
+	Active                       *bool       `json:"active,omitempty"`
+	CCRFollowParameters
+}
+
+// resourceElasticsearchCCRAutoFollowPattern handle the auto follow pattern API call
+func resourceElasticsearchCCRAutoFollowPattern() *schema.Resource {
+	resourceSchema := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"remote_cluster": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"leader_index_patterns": {
+			Type:     schema.TypeList,
+			Required: true,
+			MinItems: 1,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"leader_index_exclusion_patterns": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"follow_index_pattern": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The name of follower index, like `{{leader_index}}-follower`",
+		},
+		"settings": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			DiffSuppressFunc: suppressEquivalentJSON,
+		},
+		"active": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Pause or resume the auto follow pattern",
+		},
+	}
+	for key, value := range ccrFollowParametersSchema() {
+		resourceSchema[key] = value
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchCCRAutoFollowPatternCreate,
+		ReadContext:   resourceElasticsearchCCRAutoFollowPatternRead,
+		UpdateContext: resourceElasticsearchCCRAutoFollowPatternUpdate,
+		DeleteContext: resourceElasticsearchCCRAutoFollowPatternDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: resourceSchema,
+	}
+}
+
+// resourceElasticsearchCCRAutoFollowPatternCreate create new auto follow pattern in Elasticsearch
+func resourceElasticsearchCCRAutoFollowPatternCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	if err := meta.(*ProviderMeta).checkLicense("platinum", "Cross cluster replication"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := createCCRAutoFollowPattern(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(name)
+
+	tflog.Info(ctx, "Created auto follow pattern successfully")
+
+	if !d.Get("active").(bool) {
+		if err := pauseCCRAutoFollowPattern(ctx, name, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceElasticsearchCCRAutoFollowPatternRead(ctx, d, meta)
+}
+
+// resourceElasticsearchCCRAutoFollowPatternRead read existing auto follow pattern in Elasticsearch
+func resourceElasticsearchCCRAutoFollowPatternRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	pattern, err := getCCRAutoFollowPattern(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if pattern == nil {
+		tflog.Warn(ctx, "Auto follow pattern not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	tflog.Debug(ctx, "Get auto follow pattern successfully", "pattern", pattern.String())
+
+	d.Set("name", id)
+	d.Set("remote_cluster", pattern.RemoteCluster)
+	d.Set("leader_index_patterns", pattern.LeaderIndexPatterns)
+	d.Set("leader_index_exclusion_patterns", pattern.LeaderIndexExclusionPatterns)
+	d.Set("follow_index_pattern", pattern.FollowIndexPattern)
+	d.Set("active", pattern.Active == nil || *pattern.Active)
+	flattenCCRFollowParameters(d, &pattern.CCRFollowParameters)
+
+	flattenSettings, err := convertInterfaceToJsonString(pattern.Settings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("settings", flattenSettings)
+
+	return nil
+}
+
+// resourceElasticsearchCCRAutoFollowPatternUpdate update existing auto follow pattern and pause or resume it
+func resourceElasticsearchCCRAutoFollowPatternUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	if d.HasChangesExcept("active") {
+		err := createCCRAutoFollowPattern(ctx, d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		tflog.Info(ctx, "Updated auto follow pattern successfully")
+	}
+
+	// The put API can resume paused pattern, so the active state is reconciled with the one returned by Elasticsearch
+	pattern, err := getCCRAutoFollowPattern(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if pattern == nil {
+		return diag.Errorf("Auto follow pattern %s not found", id)
+	}
+	active := pattern.Active == nil || *pattern.Active
+	if d.Get("active").(bool) && !active {
+		if err := resumeCCRAutoFollowPattern(ctx, id, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	} else if !d.Get("active").(bool) && active {
+		if err := pauseCCRAutoFollowPattern(ctx, id, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceElasticsearchCCRAutoFollowPatternRead(ctx, d, meta)
+}
+
+// resourceElasticsearchCCRAutoFollowPatternDelete delete existing auto follow pattern in Elasticsearch
+// The follower indices already created by the pattern are kept
+func resourceElasticsearchCCRAutoFollowPatternDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.CCR.DeleteAutoFollowPattern(
+		id,
+		client.API.CCR.DeleteAutoFollowPattern.WithContext(ctx),
+		client.API.CCR.DeleteAutoFollowPattern.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Auto follow pattern not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete auto follow pattern %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted auto follow pattern successfully")
+	return nil
+}
+
+// Print CCRAutoFollowPatternSpec object as Json string
+func (r *CCRAutoFollowPatternSpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// getCCRAutoFollowPattern return the auto follow pattern from Elasticsearch, or nil if not found
+func getCCRAutoFollowPattern(ctx context.Context, name string, meta interface{}) (*CCRAutoFollowPatternSpec, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.CCR.GetAutoFollowPattern(
+		client.API.CCR.GetAutoFollowPattern.WithName(name),
+		client.API.CCR.GetAutoFollowPattern.WithContext(ctx),
+		client.API.CCR.GetAutoFollowPattern.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get auto follow pattern %s: %s", name, res.String())
+	}
+
+	patterns := &CCRAutoFollowPatterns{}
+	if err := json.NewDecoder(res.Body).Decode(patterns); err != nil {
+		return nil, err
+	}
+	for _, pattern := range patterns.Patterns {
+		if pattern.Name == name {
+			return pattern.Pattern, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// createCCRAutoFollowPattern create or update auto follow pattern in Elasticsearch
+func createCCRAutoFollowPattern(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("name").(string)
+	remoteCluster := d.Get("remote_cluster").(string)
+
+	if err := checkRemoteCluster(ctx, remoteCluster, meta); err != nil {
+		return err
+	}
+
+	pattern := &CCRAutoFollowPatternSpec{
+		RemoteCluster:                remoteCluster,
+		LeaderIndexPatterns:          convertArrayInterfaceToArrayString(d.Get("leader_index_patterns").([]interface{})),
+		LeaderIndexExclusionPatterns: convertArrayInterfaceToArrayString(d.Get("leader_index_exclusion_patterns").([]interface{})),
+		FollowIndexPattern:           d.Get("follow_index_pattern").(string),
+		Settings:                     optionalInterfaceJSON(d.Get("settings").(string)),
+		CCRFollowParameters:          buildCCRFollowParameters(d),
+	}
+	tflog.Debug(ctx, "Auto follow pattern", "pattern", pattern.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.CCR.PutAutoFollowPattern(
+		name,
+		bytes.NewReader([]byte(pattern.String())),
+		client.API.CCR.PutAutoFollowPattern.WithContext(ctx),
+		client.API.CCR.PutAutoFollowPattern.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when add auto follow pattern %s: %s", name, res.String())
+	}
+
+	return nil
+}
+
+// pauseCCRAutoFollowPattern pause the auto follow pattern
+func pauseCCRAutoFollowPattern(ctx context.Context, name string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.CCR.PauseAutoFollowPattern(
+		name,
+		client.API.CCR.PauseAutoFollowPattern.WithContext(ctx),
+		client.API.CCR.PauseAutoFollowPattern.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when pause auto follow pattern %s: %s", name, res.String())
+	}
+
+	tflog.Info(ctx, "Paused auto follow pattern successfully")
+
+	return nil
+}
+
+// resumeCCRAutoFollowPattern resume the auto follow pattern
+func resumeCCRAutoFollowPattern(ctx context.Context, name string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.CCR.ResumeAutoFollowPattern(
+		name,
+		client.API.CCR.ResumeAutoFollowPattern.WithContext(ctx),
+		client.API.CCR.ResumeAutoFollowPattern.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when resume auto follow pattern %s: %s", name, res.String())
+	}
+
+	tflog.Info(ctx, "Resumed auto follow pattern successfully")
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchCCRAutoFollowPattern(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchCCRAutoFollowPatternDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchCCRAutoFollowPattern,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchCCRAutoFollowPatternExists("elasticsearch_ccr_auto_follow_pattern.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ccr_auto_follow_pattern.test", "active", "true"),
+				),
+			},
+			{
+				Config: testElasticsearchCCRAutoFollowPatternUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchCCRAutoFollowPatternExists("elasticsearch_ccr_auto_follow_pattern.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ccr_auto_follow_pattern.test", "follow_index_pattern", "{{leader_index}}-copy"),
+					resource.TestCheckResourceAttr("elasticsearch_ccr_auto_follow_pattern.test", "active", "false"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_ccr_auto_follow_pattern.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestCCRAutoFollowPatternUpdateActive(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "terraform-test",
+		Attributes: map[string]string{
+			"name":                    "terraform-test",
+			"remote_cluster":          "leader",
+			"leader_index_patterns.#": "1",
+			"leader_index_patterns.0": "logs-*",
+			"active":                  "true",
+		},
+	}
+	diff := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"leader_index_patterns.0": {
+				Old: "logs-*",
+				New: "metrics-*",
+			},
+		},
+	}
+	d, err := schema.InternalMap(resourceElasticsearchCCRAutoFollowPattern().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The pattern is paused on Elasticsearch, so it must be resumed even if active not change on resource
+	calls := make([]string, 0)
+	meta, server := newTestProviderMeta(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+		switch r.URL.Path {
+		case "/_remote/info":
+			w.Write([]byte(`{"leader": {"connected": true, "mode": "sniff"}}`))
+		case "/_ccr/auto_follow/terraform-test":
+			if r.Method == "GET" {
+				w.Write([]byte(`{"patterns": [{"name": "terraform-test", "pattern": {"remote_cluster": "leader", "leader_index_patterns": ["metrics-*"], "active": false}}]}`))
+				return
+			}
+			w.Write([]byte(`{"acknowledged": true}`))
+		default:
+			w.Write([]byte(`{"acknowledged": true}`))
+		}
+	})
+	defer server.Close()
+
+	if diags := resourceElasticsearchCCRAutoFollowPatternUpdate(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("err: %+v", diags)
+	}
+
+	expected := []string{
+		"GET /_remote/info",
+		"PUT /_ccr/auto_follow/terraform-test",
+		"GET /_ccr/auto_follow/terraform-test",
+		"POST /_ccr/auto_follow/terraform-test/resume",
+		"GET /_ccr/auto_follow/terraform-test",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Auto follow pattern must be updated then resumed, expected %+v, got %+v", expected, calls)
+	}
+}
+
+func TestCheckRemoteCluster(t *testing.T) {
+	tests := []struct {
+		response string
+		failed   bool
+	}{
+		{`{"leader": {"connected": true, "mode": "sniff"}}`, false},
+		{`{"leader": {"connected": false, "mode": "sniff"}}`, true},
+		{`{"other": {"connected": true, "mode": "sniff"}}`, true},
+	}
+
+	for _, test := range tests {
+		meta, server := newTestProviderMeta(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(test.response))
+		})
+		err := checkRemoteCluster(context.Background(), "leader", meta)
+		server.Close()
+
+		if (err != nil) != test.failed {
+			t.Errorf("Remote clusters %s must failed: %t, got %v", test.response, test.failed, err)
+		}
+	}
+}
+
+func testCheckElasticsearchCCRAutoFollowPatternExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No auto follow pattern ID is set")
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.CCR.GetAutoFollowPattern(
+			client.API.CCR.GetAutoFollowPattern.WithName(rs.Primary.ID),
+			client.API.CCR.GetAutoFollowPattern.WithContext(context.Background()),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when get auto follow pattern %s: %s", rs.Primary.ID, res.String())
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchCCRAutoFollowPatternDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_ccr_auto_follow_pattern" {
+			continue
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.CCR.GetAutoFollowPattern(
+			client.API.CCR.GetAutoFollowPattern.WithName(rs.Primary.ID),
+			client.API.CCR.GetAutoFollowPattern.WithContext(context.Background()),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if !res.IsError() {
+			return fmt.Errorf("Auto follow pattern %q still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testElasticsearchCCRAutoFollowPattern = `
+resource "elasticsearch_cluster_settings" "test_ccr" {
+  persistent = <<EOF
+{
+  "cluster.remote.terraform-test.seeds": ["127.0.0.1:9300"]
+}
+EOF
+}
+
+resource "elasticsearch_ccr_auto_follow_pattern" "test" {
+  name                  = "terraform-test"
+  remote_cluster        = "terraform-test"
+  leader_index_patterns = ["terraform-test-auto-follow-*"]
+  follow_index_pattern  = "{{leader_index}}-follower"
+
+  depends_on = [elasticsearch_cluster_settings.test_ccr]
+}
+`
+
+var testElasticsearchCCRAutoFollowPatternUpdate = `
+resource "elasticsearch_cluster_settings" "test_ccr" {
+  persistent = <<EOF
+{
+  "cluster.remote.terraform-test.seeds": ["127.0.0.1:9300"]
+}
+EOF
+}
+
+resource "elasticsearch_ccr_auto_follow_pattern" "test" {
+  name                  = "terraform-test"
+  remote_cluster        = "terraform-test"
+  leader_index_patterns = ["terraform-test-auto-follow-*"]
+  follow_index_pattern  = "{{leader_index}}-copy"
+  active                = false
+
+  depends_on = [elasticsearch_cluster_settings.test_ccr]
+}
+`
@@ -0,0 +1,419 @@
+// Manage the cross cluster replication follower index in elasticsearch
+// The follower is paused and resumed to update the follow parameters
+// On destroy, the follower is paused, closed and unfollowed. The index is kept as closed regular index
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/ccr-apis.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// CCRFollowerIndexSpec is the follower index specification
+type CCRFollowerIndexSpec struct {
+	RemoteCluster string      `json:"remote_cluster"`
+	LeaderIndex   string      `json:"leader_index"`
+	Settings      interface{} `json:"settings,omitempty"`
+	CCRFollowParameters
+}
+
+// CCRFollowParameters is the parameters used to replicate the leader index
+type CCRFollowParameters struct {
+	MaxReadRequestOperationCount  int    `json:"max_read_request_operation_count,omitempty"`
+	MaxOutstandingReadRequests    int    `json:"max_outstanding_read_requests,omitempty"`
+	MaxReadRequestSize            string `json:"max_read_request_size,omitempty"`
+	MaxWriteRequestOperationCount int    `json:"max_write_request_operation_count,omitempty"`
+	MaxWriteRequestSize           string `json:"max_write_request_size,omitempty"`
+	MaxOutstandingWriteRequests   int    `json:"max_outstanding_write_requests,omitempty"`
+	MaxWriteBufferCount           int    `json:"max_write_buffer_count,omitempty"`
+	MaxWriteBufferSize            string `json:"max_write_buffer_size,omitempty"`
+	MaxRetryDelay                 string `json:"max_retry_delay,omitempty"`
+	ReadPollTimeout               string `json:"read_poll_timeout,omitempty"`
+}
+
+// CCRFollowInfo is the follower indices info returned by API
+type CCRFollowInfo struct {
+	FollowerIndices []*CCRFollowerIndexInfo `json:"follower_indices"`
+}
+
+// CCRFollowerIndexInfo is the follower index info
+type CCRFollowerIndexInfo struct {
+	FollowerIndex string               `json:"follower_index"`
+	RemoteCluster string               `json:"remote_cluster"`
+	LeaderIndex   string               `json:"leader_index"`
+	Status        string               `json:"status"`
+	Parameters    *CCRFollowParameters `json:"parameters,omitempty"`
+}
+
+// resourceElasticsearchCCRFollowerIndex handle the follower index API call
+func resourceElasticsearchCCRFollowerIndex() *schema.Resource {
+	resourceSchema := map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The follower index name",
+		},
+		"remote_cluster": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"leader_index": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"settings": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ForceNew:         true,
+			DiffSuppressFunc: suppressEquivalentJSON,
+		},
+		"active": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Pause or resume the replication",
+		},
+	}
+	for key, value := range ccrFollowParametersSchema() {
+		resourceSchema[key] = value
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchCCRFollowerIndexCreate,
+		ReadContext:   resourceElasticsearchCCRFollowerIndexRead,
+		UpdateContext: resourceElasticsearchCCRFollowerIndexUpdate,
+		DeleteContext: resourceElasticsearchCCRFollowerIndexDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: resourceSchema,
+	}
+}
+
+// resourceElasticsearchCCRFollowerIndexCreate create new follower index in Elasticsearch
+func resourceElasticsearchCCRFollowerIndexCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	if err := meta.(*ProviderMeta).checkLicense("platinum", "Cross cluster replication"); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := checkRemoteCluster(ctx, d.Get("remote_cluster").(string), meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	follower := &CCRFollowerIndexSpec{
+		RemoteCluster:       d.Get("remote_cluster").(string),
+		LeaderIndex:         d.Get("leader_index").(string),
+		Settings:            optionalInterfaceJSON(d.Get("settings").(string)),
+		CCRFollowParameters: buildCCRFollowParameters(d),
+	}
+	tflog.Debug(ctx, "Follower index", "follower", follower.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.CCR.Follow(
+		name,
+		bytes.NewReader([]byte(follower.String())),
+		client.API.CCR.Follow.WithContext(ctx),
+		client.API.CCR.Follow.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when create follower index %s: %s", name, res.String())
+	}
+
+	d.SetId(name)
+
+	tflog.Info(ctx, "Created follower index successfully")
+
+	if !d.Get("active").(bool) {
+		if err := pauseCCRFollowerIndex(ctx, name, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceElasticsearchCCRFollowerIndexRead(ctx, d, meta)
+}
+
+// resourceElasticsearchCCRFollowerIndexRead read existing follower index in Elasticsearch
+// The follow parameters are only returned when the follower is active, so they are kept from state when it's paused
+func resourceElasticsearchCCRFollowerIndexRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	follower, err := getCCRFollowerIndex(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if follower == nil {
+		tflog.Warn(ctx, "Follower index not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	tflog.Debug(ctx, "Get follower index successfully", "status", follower.Status)
+
+	d.Set("name", follower.FollowerIndex)
+	d.Set("remote_cluster", follower.RemoteCluster)
+	d.Set("leader_index", follower.LeaderIndex)
+	d.Set("active", follower.Status == "active")
+	if follower.Parameters != nil {
+		flattenCCRFollowParameters(d, follower.Parameters)
+	}
+
+	return nil
+}
+
+// resourceElasticsearchCCRFollowerIndexUpdate update the follow parameters and pause or resume the follower index
+func resourceElasticsearchCCRFollowerIndexUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	o, n := d.GetChange("active")
+	wasActive := o.(bool)
+	isActive := n.(bool)
+	parametersChanged := d.HasChangesExcept("active")
+
+	// Follow parameters can only be changed when resume the follower
+	if wasActive && (parametersChanged || !isActive) {
+		if err := pauseCCRFollowerIndex(ctx, id, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if isActive && (parametersChanged || !wasActive) {
+		if err := resumeCCRFollowerIndex(ctx, id, buildCCRFollowParameters(d), meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	tflog.Info(ctx, "Updated follower index successfully")
+
+	return resourceElasticsearchCCRFollowerIndexRead(ctx, d, meta)
+}
+
+// resourceElasticsearchCCRFollowerIndexDelete pause, close and unfollow the follower index in Elasticsearch
+// The index is not deleted, it's kept as closed regular index
+func resourceElasticsearchCCRFollowerIndexDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	follower, err := getCCRFollowerIndex(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if follower == nil {
+		tflog.Warn(ctx, "Follower index not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	if follower.Status == "active" {
+		if err := pauseCCRFollowerIndex(ctx, id, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.Close(
+		[]string{id},
+		client.API.Indices.Close.WithContext(ctx),
+		client.API.Indices.Close.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when close follower index %s: %s", id, res.String())
+	}
+
+	res, err = client.API.CCR.Unfollow(
+		id,
+		client.API.CCR.Unfollow.WithContext(ctx),
+		client.API.CCR.Unfollow.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when unfollow follower index %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Unfollowed follower index successfully")
+	return nil
+}
+
+// Print CCRFollowerIndexSpec object as Json string
+func (r *CCRFollowerIndexSpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// ccrFollowParametersSchema return the schema of follow parameters, shared by follower index and auto follow pattern
+// They are computed because Elasticsearch return the default values
+func ccrFollowParametersSchema() map[string]*schema.Schema {
+	result := make(map[string]*schema.Schema)
+
+	for _, key := range []string{
+		"max_read_request_operation_count",
+		"max_outstanding_read_requests",
+		"max_write_request_operation_count",
+		"max_outstanding_write_requests",
+		"max_write_buffer_count",
+	} {
+		result[key] = &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		}
+	}
+
+	for _, key := range []string{
+		"max_read_request_size",
+		"max_write_request_size",
+		"max_write_buffer_size",
+		"max_retry_delay",
+		"read_poll_timeout",
+	} {
+		result[key] = &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		}
+	}
+
+	return result
+}
+
+// buildCCRFollowParameters return the follow parameters from resource data
+func buildCCRFollowParameters(d *schema.ResourceData) CCRFollowParameters {
+	return CCRFollowParameters{
+		MaxReadRequestOperationCount:  d.Get("max_read_request_operation_count").(int),
+		MaxOutstandingReadRequests:    d.Get("max_outstanding_read_requests").(int),
+		MaxReadRequestSize:            d.Get("max_read_request_size").(string),
+		MaxWriteRequestOperationCount: d.Get("max_write_request_operation_count").(int),
+		MaxWriteRequestSize:           d.Get("max_write_request_size").(string),
+		MaxOutstandingWriteRequests:   d.Get("max_outstanding_write_requests").(int),
+		MaxWriteBufferCount:           d.Get("max_write_buffer_count").(int),
+		MaxWriteBufferSize:            d.Get("max_write_buffer_size").(string),
+		MaxRetryDelay:                 d.Get("max_retry_delay").(string),
+		ReadPollTimeout:               d.Get("read_poll_timeout").(string),
+	}
+}
+
+// flattenCCRFollowParameters set the follow parameters on resource data
+func flattenCCRFollowParameters(d *schema.ResourceData, parameters *CCRFollowParameters) {
+	d.Set("max_read_request_operation_count", parameters.MaxReadRequestOperationCount)
+	d.Set("max_outstanding_read_requests", parameters.MaxOutstandingReadRequests)
+	d.Set("max_read_request_size", parameters.MaxReadRequestSize)
+	d.Set("max_write_request_operation_count", parameters.MaxWriteRequestOperationCount)
+	d.Set("max_write_request_size", parameters.MaxWriteRequestSize)
+	d.Set("max_outstanding_write_requests", parameters.MaxOutstandingWriteRequests)
+	d.Set("max_write_buffer_count", parameters.MaxWriteBufferCount)
+	d.Set("max_write_buffer_size", parameters.MaxWriteBufferSize)
+	d.Set("max_retry_delay", parameters.MaxRetryDelay)
+	d.Set("read_poll_timeout", parameters.ReadPollTimeout)
+}
+
+// getCCRFollowerIndex return the follower index info from Elasticsearch, or nil if not found or not follower index
+func getCCRFollowerIndex(ctx context.Context, name string, meta interface{}) (*CCRFollowerIndexInfo, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.CCR.FollowInfo(
+		[]string{name},
+		client.API.CCR.FollowInfo.WithContext(ctx),
+		client.API.CCR.FollowInfo.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get follower index %s: %s", name, res.String())
+	}
+
+	followInfo := &CCRFollowInfo{}
+	if err := json.NewDecoder(res.Body).Decode(followInfo); err != nil {
+		return nil, err
+	}
+
+	for _, follower := range followInfo.FollowerIndices {
+		if follower.FollowerIndex == name {
+			return follower, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// pauseCCRFollowerIndex pause the follower index
+func pauseCCRFollowerIndex(ctx context.Context, name string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.CCR.PauseFollow(
+		name,
+		client.API.CCR.PauseFollow.WithContext(ctx),
+		client.API.CCR.PauseFollow.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when pause follower index %s: %s", name, res.String())
+	}
+
+	tflog.Info(ctx, "Paused follower index successfully")
+
+	return nil
+}
+
+// resumeCCRFollowerIndex resume the follower index with the follow parameters
+func resumeCCRFollowerIndex(ctx context.Context, name string, parameters CCRFollowParameters, meta interface{}) error {
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.CCR.ResumeFollow(
+		name,
+		client.API.CCR.ResumeFollow.WithBody(bytes.NewReader(data)),
+		client.API.CCR.ResumeFollow.WithContext(ctx),
+		client.API.CCR.ResumeFollow.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when resume follower index %s: %s", name, res.String())
+	}
+
+	tflog.Info(ctx, "Resumed follower index successfully")
+
+	return nil
+}
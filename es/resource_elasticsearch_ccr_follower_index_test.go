@@ -0,0 +1,170 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchCCRFollowerIndex(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchCCRFollowerIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testElasticsearchCCRFollowerIndexBadRemoteCluster,
+				ExpectError: regexp.MustCompile("Remote cluster terraform-test-missing not found"),
+			},
+			{
+				Config: testElasticsearchCCRFollowerIndex,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchCCRFollowerIndexExists("elasticsearch_ccr_follower_index.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ccr_follower_index.test", "active", "true"),
+				),
+			},
+			{
+				Config: testElasticsearchCCRFollowerIndexUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchCCRFollowerIndexExists("elasticsearch_ccr_follower_index.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ccr_follower_index.test", "max_read_request_operation_count", "1024"),
+				),
+			},
+			{
+				Config: testElasticsearchCCRFollowerIndexPause,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchCCRFollowerIndexExists("elasticsearch_ccr_follower_index.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ccr_follower_index.test", "active", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchCCRFollowerIndexExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No follower index ID is set")
+		}
+
+		follower, err := getCCRFollowerIndex(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if follower == nil {
+			return errors.Errorf("Follower index %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchCCRFollowerIndexDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_ccr_follower_index" {
+			continue
+		}
+
+		follower, err := getCCRFollowerIndex(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if follower != nil {
+			return fmt.Errorf("Follower index %q is still following", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testElasticsearchCCRFollowerIndexBadRemoteCluster = `
+resource "elasticsearch_ccr_follower_index" "test" {
+  name           = "terraform-test-follower"
+  remote_cluster = "terraform-test-missing"
+  leader_index   = "terraform-test-leader"
+}
+`
+
+var testElasticsearchCCRFollowerIndex = `
+resource "elasticsearch_cluster_settings" "test_ccr" {
+  persistent = <<EOF
+{
+  "cluster.remote.terraform-test.seeds": ["127.0.0.1:9300"]
+}
+EOF
+}
+
+resource "elasticsearch_index" "test_leader" {
+  name                = "terraform-test-leader"
+  deletion_protection = false
+}
+
+resource "elasticsearch_ccr_follower_index" "test" {
+  name           = "terraform-test-follower"
+  remote_cluster = "terraform-test"
+  leader_index   = elasticsearch_index.test_leader.name
+
+  depends_on = [elasticsearch_cluster_settings.test_ccr]
+}
+`
+
+var testElasticsearchCCRFollowerIndexUpdate = `
+resource "elasticsearch_cluster_settings" "test_ccr" {
+  persistent = <<EOF
+{
+  "cluster.remote.terraform-test.seeds": ["127.0.0.1:9300"]
+}
+EOF
+}
+
+resource "elasticsearch_index" "test_leader" {
+  name                = "terraform-test-leader"
+  deletion_protection = false
+}
+
+resource "elasticsearch_ccr_follower_index" "test" {
+  name                             = "terraform-test-follower"
+  remote_cluster                   = "terraform-test"
+  leader_index                     = elasticsearch_index.test_leader.name
+  max_read_request_operation_count = 1024
+
+  depends_on = [elasticsearch_cluster_settings.test_ccr]
+}
+`
+
+var testElasticsearchCCRFollowerIndexPause = `
+resource "elasticsearch_cluster_settings" "test_ccr" {
+  persistent = <<EOF
+{
+  "cluster.remote.terraform-test.seeds": ["127.0.0.1:9300"]
+}
+EOF
+}
+
+resource "elasticsearch_index" "test_leader" {
+  name                = "terraform-test-leader"
+  deletion_protection = false
+}
+
+resource "elasticsearch_ccr_follower_index" "test" {
+  name                             = "terraform-test-follower"
+  remote_cluster                   = "terraform-test"
+  leader_index                     = elasticsearch_index.test_leader.name
+  max_read_request_operation_count = 1024
+  active                           = false
+
+  depends_on = [elasticsearch_cluster_settings.test_ccr]
+}
+`
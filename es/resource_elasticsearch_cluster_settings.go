@@ -0,0 +1,271 @@
+// Manage cluster settings in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/cluster-update-settings.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// clusterSettingsID is the ID of cluster settings resource, because there are only one cluster settings
+const clusterSettingsID = "cluster_settings"
+
+// ClusterSettings is the cluster settings object
+type ClusterSettings struct {
+	Persistent map[string]interface{} `json:"persistent,omitempty"`
+	Transient  map[string]interface{} `json:"transient,omitempty"`
+}
+
+// resourceElasticsearchClusterSettings handle the cluster settings API call
+func resourceElasticsearchClusterSettings() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchClusterSettingsCreate,
+		ReadContext:   resourceElasticsearchClusterSettingsRead,
+		UpdateContext: resourceElasticsearchClusterSettingsUpdate,
+		DeleteContext: resourceElasticsearchClusterSettingsDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceElasticsearchClusterSettingsImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"persistent": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressClusterSettings,
+				Description:      "The persistent settings as JSON object. Only settings set here are managed",
+			},
+			"transient": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressClusterSettings,
+				Description:      "The transient settings as JSON object. Only settings set here are managed",
+			},
+		},
+	}
+}
+
+// resourceElasticsearchClusterSettingsCreate set the cluster settings
+func resourceElasticsearchClusterSettingsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, clusterSettingsID)
+
+	err := updateClusterSettings(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(clusterSettingsID)
+
+	tflog.Info(ctx, "Set cluster settings successfully")
+
+	return resourceElasticsearchClusterSettingsRead(ctx, d, meta)
+}
+
+// resourceElasticsearchClusterSettingsRead read the cluster settings managed by Terraform
+func resourceElasticsearchClusterSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Cluster.GetSettings(
+		client.API.Cluster.GetSettings.WithFlatSettings(true),
+		client.API.Cluster.GetSettings.WithContext(ctx),
+		client.API.Cluster.GetSettings.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when get cluster settings: %s", res.String())
+	}
+
+	clusterSettings := &ClusterSettings{}
+	if err := json.NewDecoder(res.Body).Decode(clusterSettings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	persistent, err := filterClusterSettings(d.Get("persistent").(string), clusterSettings.Persistent)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	transient, err := filterClusterSettings(d.Get("transient").(string), clusterSettings.Transient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Get cluster settings successfully", "persistent", persistent, "transient", transient)
+
+	d.Set("persistent", persistent)
+	d.Set("transient", transient)
+
+	return nil
+}
+
+// resourceElasticsearchClusterSettingsImport import the cluster settings without any managed setting.
+// The cluster has a lot of settings not managed by Terraform, so import them all will reset them on next apply.
+// The settings declared on resource are then set on next apply and read from API after.
+func resourceElasticsearchClusterSettingsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if d.Id() != clusterSettingsID {
+		return nil, errors.Errorf("Cluster settings must be imported with ID %s, got %s", clusterSettingsID, d.Id())
+	}
+
+	d.Set("persistent", "")
+	d.Set("transient", "")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceElasticsearchClusterSettingsUpdate update the cluster settings
+func resourceElasticsearchClusterSettingsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := updateClusterSettings(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "Updated cluster settings successfully")
+
+	return resourceElasticsearchClusterSettingsRead(ctx, d, meta)
+}
+
+// resourceElasticsearchClusterSettingsDelete reset the cluster settings managed by Terraform
+func resourceElasticsearchClusterSettingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	persistent, err := flattenClusterSettingsJSON(d.Get("persistent").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	transient, err := flattenClusterSettingsJSON(d.Get("transient").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	clusterSettings := &ClusterSettings{
+		Persistent: make(map[string]interface{}),
+		Transient:  make(map[string]interface{}),
+	}
+	for key := range persistent {
+		clusterSettings.Persistent[key] = nil
+	}
+	for key := range transient {
+		clusterSettings.Transient[key] = nil
+	}
+
+	if len(clusterSettings.Persistent) > 0 || len(clusterSettings.Transient) > 0 {
+		if err := putClusterSettings(ctx, clusterSettings, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Reset cluster settings successfully")
+	return nil
+}
+
+// updateClusterSettings set the settings added or changed, and reset the settings removed from config
+func updateClusterSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	clusterSettings := &ClusterSettings{}
+
+	for _, key := range []string{"persistent", "transient"} {
+		o, n := d.GetChange(key)
+		oldSettings, err := flattenClusterSettingsJSON(o.(string))
+		if err != nil {
+			return err
+		}
+		newSettings, err := flattenClusterSettingsJSON(n.(string))
+		if err != nil {
+			return err
+		}
+
+		settings := make(map[string]interface{})
+		for _, setting := range diffSettings(oldSettings, newSettings) {
+			settings[setting] = newSettings[setting]
+		}
+
+		if key == "persistent" {
+			clusterSettings.Persistent = settings
+		} else {
+			clusterSettings.Transient = settings
+		}
+	}
+
+	if len(clusterSettings.Persistent) == 0 && len(clusterSettings.Transient) == 0 {
+		return nil
+	}
+
+	return putClusterSettings(ctx, clusterSettings, meta)
+}
+
+// putClusterSettings call the API to update cluster settings
+func putClusterSettings(ctx context.Context, clusterSettings *ClusterSettings, meta interface{}) error {
+	tflog.Debug(ctx, "Cluster settings", "settings", clusterSettings.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Cluster.PutSettings(
+		bytes.NewReader([]byte(clusterSettings.String())),
+		client.API.Cluster.PutSettings.WithContext(ctx),
+		client.API.Cluster.PutSettings.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when update cluster settings: %s", res.String())
+	}
+
+	return nil
+}
+
+// Print ClusterSettings object as Json string
+func (r *ClusterSettings) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// flattenClusterSettingsJSON permit to convert settings as JSON string on flat settings.
+// Dotted and nested forms are first undotted with parseAllDotProperties, so the both give the same flat settings
+func flattenClusterSettingsJSON(settings string) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if settings == "" {
+		return result, nil
+	}
+
+	data := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(settings), &data); err != nil {
+		return nil, err
+	}
+	flattenSettings("", parseAllDotProperties(data), result)
+
+	return result, nil
+}
+
+// filterClusterSettings return, as JSON string, only the settings from API that are managed on state
+func filterClusterSettings(currentSettings string, settings map[string]interface{}) (string, error) {
+	managedSettings, err := flattenClusterSettingsJSON(currentSettings)
+	if err != nil {
+		return "", err
+	}
+
+	result := make(map[string]interface{})
+	for key, value := range settings {
+		if _, ok := managedSettings[key]; ok {
+			result[key] = value
+		}
+	}
+
+	return convertInterfaceToJsonString(result)
+}
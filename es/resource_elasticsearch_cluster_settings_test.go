@@ -0,0 +1,234 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchClusterSettings(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchClusterSettingsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchClusterSettings,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchClusterSettingsExists("elasticsearch_cluster_settings.test", "persistent", "cluster.routing.allocation.disk.watermark.low"),
+				),
+			},
+			{
+				Config: testElasticsearchClusterSettingsUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchClusterSettingsExists("elasticsearch_cluster_settings.test", "transient", "indices.recovery.max_bytes_per_sec"),
+				),
+			},
+		},
+	})
+}
+
+func TestFlattenClusterSettingsJSON(t *testing.T) {
+	expected := map[string]interface{}{
+		"cluster.routing.allocation.disk.watermark.low":  "80%",
+		"cluster.routing.allocation.disk.watermark.high": "90%",
+		"cluster.max_shards_per_node":                    "2000",
+	}
+
+	for _, settings := range []string{
+		`{"cluster.routing.allocation.disk.watermark.low": "80%", "cluster.routing.allocation.disk.watermark.high": "90%", "cluster.max_shards_per_node": 2000}`,
+		`{"cluster": {"routing": {"allocation.disk.watermark": {"low": "80%", "high": "90%"}}, "max_shards_per_node": "2000"}}`,
+	} {
+		result, err := flattenClusterSettingsJSON(settings)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Settings %s must be flattened as %+v, got %+v", settings, expected, result)
+		}
+	}
+
+	result, err := filterClusterSettings(`{"cluster": {"max_shards_per_node": 2000}}`, map[string]interface{}{
+		"cluster.max_shards_per_node":                   "2000",
+		"cluster.routing.allocation.disk.watermark.low": "80%",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if result != `{"cluster.max_shards_per_node":"2000"}` {
+		t.Errorf("Only managed settings must be kept, got %s", result)
+	}
+}
+
+func TestClusterSettingsImportWithPartialConfig(t *testing.T) {
+	var putSettings *ClusterSettings
+	meta, server := newTestProviderMeta(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"persistent": {"cluster.max_shards_per_node": "2000", "cluster.routing.allocation.disk.watermark.low": "80%", "xpack.monitoring.collection.enabled": "true"}, "transient": {"indices.recovery.max_bytes_per_sec": "50mb"}}`))
+		case "PUT":
+			putSettings = &ClusterSettings{}
+			json.NewDecoder(r.Body).Decode(putSettings)
+			w.Write([]byte(`{"acknowledged": true}`))
+		}
+	})
+	defer server.Close()
+
+	r := resourceElasticsearchClusterSettings()
+	d := r.TestResourceData()
+	d.SetId(clusterSettingsID)
+	imported, err := r.Importer.StateContext(context.Background(), d, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diags := r.ReadContext(context.Background(), imported[0], meta); diags.HasError() {
+		t.Fatalf("err: %+v", diags)
+	}
+	if imported[0].Get("persistent").(string) != "" || imported[0].Get("transient").(string) != "" {
+		t.Fatalf("No setting must be imported, got persistent %s and transient %s", imported[0].Get("persistent"), imported[0].Get("transient"))
+	}
+
+	// Apply config that only manage one setting
+	state := imported[0].State()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"persistent": `{"cluster.max_shards_per_node": 2000}`,
+	})
+	diff, err := r.Diff(context.Background(), state, config, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d, err = schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diags := r.UpdateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("err: %+v", diags)
+	}
+
+	expected := &ClusterSettings{
+		Persistent: map[string]interface{}{
+			"cluster.max_shards_per_node": "2000",
+		},
+	}
+	if !reflect.DeepEqual(putSettings, expected) {
+		t.Errorf("Only the setting on config must be set, got %s", putSettings)
+	}
+	if d.Get("persistent").(string) != `{"cluster.max_shards_per_node":"2000"}` {
+		t.Errorf("Only the setting on config must be read, got %s", d.Get("persistent"))
+	}
+}
+
+func testCheckElasticsearchClusterSettingsExists(name string, settingsType string, key string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No cluster settings ID is set")
+		}
+
+		clusterSettings, err := testGetClusterSettings()
+		if err != nil {
+			return err
+		}
+
+		settings := clusterSettings.Persistent
+		if settingsType == "transient" {
+			settings = clusterSettings.Transient
+		}
+		if _, ok := settings[key]; !ok {
+			return errors.Errorf("Cluster setting %s not found on %s settings", key, settingsType)
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchClusterSettingsDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_cluster_settings" {
+			continue
+		}
+
+		clusterSettings, err := testGetClusterSettings()
+		if err != nil {
+			return err
+		}
+
+		if _, ok := clusterSettings.Persistent["cluster.routing.allocation.disk.watermark.low"]; ok {
+			return fmt.Errorf("Cluster setting cluster.routing.allocation.disk.watermark.low still exists")
+		}
+		if _, ok := clusterSettings.Transient["indices.recovery.max_bytes_per_sec"]; ok {
+			return fmt.Errorf("Cluster setting indices.recovery.max_bytes_per_sec still exists")
+		}
+	}
+
+	return nil
+}
+
+func testGetClusterSettings() (*ClusterSettings, error) {
+	meta := testAccProvider.Meta()
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Cluster.GetSettings(
+		client.API.Cluster.GetSettings.WithFlatSettings(true),
+		client.API.Cluster.GetSettings.WithContext(context.Background()),
+		client.API.Cluster.GetSettings.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("Error when get cluster settings: %s", res.String())
+	}
+
+	clusterSettings := &ClusterSettings{}
+	if err := json.NewDecoder(res.Body).Decode(clusterSettings); err != nil {
+		return nil, err
+	}
+
+	return clusterSettings, nil
+}
+
+var testElasticsearchClusterSettings = `
+resource "elasticsearch_cluster_settings" "test" {
+  persistent = <<EOF
+{
+	"cluster.routing.allocation.disk.watermark.low": "80%",
+	"cluster.routing.allocation.disk.watermark.high": "90%"
+}
+EOF
+}
+`
+
+var testElasticsearchClusterSettingsUpdate = `
+resource "elasticsearch_cluster_settings" "test" {
+  persistent = <<EOF
+{
+	"cluster": {
+		"routing": {
+			"allocation.disk.watermark.low": "85%"
+		}
+	}
+}
+EOF
+  transient  = <<EOF
+{
+	"indices.recovery.max_bytes_per_sec": "50mb"
+}
+EOF
+}
+`
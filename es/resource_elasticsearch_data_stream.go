@@ -0,0 +1,278 @@
+// Manage data stream in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/data-stream-apis.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	olivere "github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
+)
+
+// DataStreams is the data streams object returned by API
+type DataStreams struct {
+	DataStreams []*DataStreamSpec `json:"data_streams"`
+}
+
+// DataStreamSpec is the data stream specification
+type DataStreamSpec struct {
+	Name       string                 `json:"name"`
+	Indices    []*DataStreamIndexSpec `json:"indices,omitempty"`
+	Generation int                    `json:"generation,omitempty"`
+	Status     string                 `json:"status,omitempty"`
+	Template   string                 `json:"template,omitempty"`
+	IlmPolicy  string                 `json:"ilm_policy,omitempty"`
+}
+
+// DataStreamIndexSpec is the backing index of data stream
+type DataStreamIndexSpec struct {
+	IndexName string `json:"index_name"`
+	IndexUUID string `json:"index_uuid"`
+}
+
+// resourceElasticsearchDataStream handle the data stream API call
+func resourceElasticsearchDataStream() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchDataStreamCreate,
+		ReadContext:   resourceElasticsearchDataStreamRead,
+		DeleteContext: resourceElasticsearchDataStreamDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"backing_indices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"generation": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"template": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ilm_policy": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceElasticsearchDataStreamCreate create data stream
+func resourceElasticsearchDataStreamCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	// Elasticsearch need index template with data_stream to create data stream
+	template, err := getDataStreamTemplate(ctx, name, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, "Found index template for data stream", "template", template)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.CreateDataStream(
+		name,
+		client.API.Indices.CreateDataStream.WithContext(ctx),
+		client.API.Indices.CreateDataStream.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when create data stream %s: %s", name, res.String())
+	}
+
+	d.SetId(name)
+	tflog.Info(ctx, "Create data stream successfully")
+
+	return resourceElasticsearchDataStreamRead(ctx, d, meta)
+}
+
+// resourceElasticsearchDataStreamRead read data stream
+func resourceElasticsearchDataStreamRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.GetDataStream(
+		client.API.Indices.GetDataStream.WithName(id),
+		client.API.Indices.GetDataStream.WithContext(ctx),
+		client.API.Indices.GetDataStream.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Data stream not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when get data stream %s: %s", id, res.String())
+	}
+
+	dataStreams := &DataStreams{}
+	if err := json.NewDecoder(res.Body).Decode(dataStreams); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var dataStream *DataStreamSpec
+	for _, ds := range dataStreams.DataStreams {
+		if ds.Name == id {
+			dataStream = ds
+			break
+		}
+	}
+	if dataStream == nil {
+		tflog.Warn(ctx, "Data stream not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	indices := make([]string, 0, len(dataStream.Indices))
+	for _, index := range dataStream.Indices {
+		indices = append(indices, index.IndexName)
+	}
+
+	tflog.Debug(ctx, "Get data stream successfully", "indices", indices, "generation", dataStream.Generation, "status", dataStream.Status)
+
+	d.Set("name", id)
+	d.Set("backing_indices", indices)
+	d.Set("generation", dataStream.Generation)
+	d.Set("template", dataStream.Template)
+	d.Set("ilm_policy", dataStream.IlmPolicy)
+	d.Set("status", dataStream.Status)
+
+	return nil
+}
+
+// resourceElasticsearchDataStreamDelete delete data stream and its backing indices
+func resourceElasticsearchDataStreamDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.DeleteDataStream(
+		[]string{id},
+		client.API.Indices.DeleteDataStream.WithContext(ctx),
+		client.API.Indices.DeleteDataStream.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Data stream not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete data stream %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// getDataStreamTemplate return the index template name used to create the data stream.
+// Like Elasticsearch, the matching template with the highest priority is used, and it must enable data_stream.
+func getDataStreamTemplate(ctx context.Context, name string, meta interface{}) (string, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.GetIndexTemplate(
+		client.API.Indices.GetIndexTemplate.WithContext(ctx),
+		client.API.Indices.GetIndexTemplate.WithPretty(),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return "", errors.Errorf("No index template match data stream %s, you need to create index template with data_stream before", name)
+		}
+		return "", errors.Errorf("Error when get index templates: %s", res.String())
+	}
+
+	indexTemplates := &olivere.IndicesGetIndexTemplateResponse{}
+	if err := json.NewDecoder(res.Body).Decode(indexTemplates); err != nil {
+		return "", err
+	}
+
+	var matchTemplate *olivere.IndicesGetIndexTemplates
+	for i, indexTemplate := range indexTemplates.IndexTemplates {
+		if indexTemplate.IndexTemplate == nil {
+			continue
+		}
+		for _, pattern := range indexTemplate.IndexTemplate.IndexPatterns {
+			if matchIndexPattern(pattern, name) {
+				if matchTemplate == nil || indexTemplate.IndexTemplate.Priority > matchTemplate.IndexTemplate.Priority {
+					matchTemplate = &indexTemplates.IndexTemplates[i]
+				}
+				break
+			}
+		}
+	}
+
+	if matchTemplate == nil {
+		return "", errors.Errorf("No index template match data stream %s, you need to create index template with data_stream before", name)
+	}
+	if matchTemplate.IndexTemplate.DataStream == nil {
+		return "", errors.Errorf("The index template %s that match data stream %s not enable data_stream", matchTemplate.Name, name)
+	}
+
+	return matchTemplate.Name, nil
+}
+
+// matchIndexPattern return true if name match the index pattern. Like Elasticsearch, only `*` wildcard is supported
+func matchIndexPattern(pattern string, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == name
+	}
+
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(name, part)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(part):]
+	}
+
+	return strings.HasSuffix(name, parts[len(parts)-1])
+}
@@ -0,0 +1,150 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchDataStream(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchDataStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testElasticsearchDataStreamWithoutTemplate,
+				ExpectError: regexp.MustCompile("No index template match data stream"),
+			},
+			{
+				Config: testElasticsearchDataStream,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchDataStreamExists("elasticsearch_data_stream.test"),
+					resource.TestCheckResourceAttr("elasticsearch_data_stream.test", "template", "terraform-test-data-stream"),
+					resource.TestCheckResourceAttr("elasticsearch_data_stream.test", "generation", "1"),
+					resource.TestCheckResourceAttr("elasticsearch_data_stream.test", "backing_indices.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_data_stream.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestMatchIndexPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"logs-*", "logs-app-default", true},
+		{"logs-*-default", "logs-app-default", true},
+		{"*", "logs", true},
+		{"logs", "logs", true},
+		{"logs-*", "metrics-app", false},
+		{"logs-*-default", "logs-app-prod", false},
+		{"a*a", "a", false},
+	}
+
+	for _, test := range tests {
+		if result := matchIndexPattern(test.pattern, test.name); result != test.expected {
+			t.Errorf("Pattern %s with name %s must return %t, got %t", test.pattern, test.name, test.expected, result)
+		}
+	}
+}
+
+func testCheckElasticsearchDataStreamExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No data stream ID is set")
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Indices.GetDataStream(
+			client.API.Indices.GetDataStream.WithName(rs.Primary.ID),
+			client.API.Indices.GetDataStream.WithContext(context.Background()),
+			client.API.Indices.GetDataStream.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when get data stream %s: %s", rs.Primary.ID, res.String())
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchDataStreamDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_data_stream" {
+			continue
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Indices.GetDataStream(
+			client.API.Indices.GetDataStream.WithName(rs.Primary.ID),
+			client.API.Indices.GetDataStream.WithContext(context.Background()),
+			client.API.Indices.GetDataStream.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			if res.StatusCode == 404 {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Data stream %q still exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+var testElasticsearchDataStreamWithoutTemplate = `
+resource "elasticsearch_data_stream" "test" {
+  name = "terraform-test-no-template"
+}
+`
+
+var testElasticsearchDataStream = `
+resource "elasticsearch_index_template" "test" {
+  name 		= "terraform-test-data-stream"
+  template 	= <<EOF
+{
+	"index_patterns": ["terraform-test-data-stream*"],
+	"data_stream": {},
+	"priority": 200
+}
+EOF
+}
+
+resource "elasticsearch_data_stream" "test" {
+  name = "terraform-test-data-stream"
+
+  depends_on = [elasticsearch_index_template.test]
+}
+`
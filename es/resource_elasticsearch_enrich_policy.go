@@ -0,0 +1,314 @@
+// Manage the enrich policy in elasticsearch
+// Enrich policy can't be updated, so any change create new enrich policy
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/enrich-apis.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+// EnrichPolicy is the enrich policy object, the key is the policy type
+type EnrichPolicy map[string]*EnrichPolicySpec
+
+// EnrichPolicySpec is the enrich policy specification
+type EnrichPolicySpec struct {
+	Name         string      `json:"name,omitempty"`
+	Indices      []string    `json:"indices"`
+	MatchField   string      `json:"match_field"`
+	EnrichFields []string    `json:"enrich_fields"`
+	Query        interface{} `json:"query,omitempty"`
+}
+
+// EnrichPolicies is the enrich policies object returned by API
+type EnrichPolicies struct {
+	Policies []*EnrichPolicyConfig `json:"policies"`
+}
+
+// EnrichPolicyConfig is the enrich policy config returned by API
+type EnrichPolicyConfig struct {
+	Config EnrichPolicy `json:"config"`
+}
+
+// EnrichPolicyExecuteResponse is the response returned when execute enrich policy
+type EnrichPolicyExecuteResponse struct {
+	Status *EnrichPolicyExecuteStatus `json:"status"`
+}
+
+// EnrichPolicyExecuteStatus is the status of enrich policy execution
+type EnrichPolicyExecuteStatus struct {
+	Phase string `json:"phase"`
+}
+
+// enrichPolicyTypes is the supported enrich policy types
+var enrichPolicyTypes = []string{"match", "geo_match", "range"}
+
+// resourceElasticsearchEnrichPolicy handle the enrich policy API call
+func resourceElasticsearchEnrichPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchEnrichPolicyCreate,
+		ReadContext:   resourceElasticsearchEnrichPolicyRead,
+		DeleteContext: resourceElasticsearchEnrichPolicyDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceElasticsearchEnrichPolicyImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"policy_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(enrichPolicyTypes, false),
+			},
+			"indices": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"match_field": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"enrich_fields": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"query": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"execute": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Execute the enrich policy after create it",
+			},
+			"last_apply_execution_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The phase of the execution done by Terraform when create the policy. It's not read from Elasticsearch",
+			},
+		},
+	}
+}
+
+// resourceElasticsearchEnrichPolicyCreate create new enrich policy in Elasticsearch and execute it if needed
+func resourceElasticsearchEnrichPolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	policy := EnrichPolicy{
+		d.Get("policy_type").(string): &EnrichPolicySpec{
+			Indices:      convertArrayInterfaceToArrayString(d.Get("indices").([]interface{})),
+			MatchField:   d.Get("match_field").(string),
+			EnrichFields: convertArrayInterfaceToArrayString(d.Get("enrich_fields").([]interface{})),
+			Query:        optionalInterfaceJSON(d.Get("query").(string)),
+		},
+	}
+	tflog.Debug(ctx, "Enrich policy", "policy", policy.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.EnrichPutPolicy(
+		name,
+		bytes.NewReader([]byte(policy.String())),
+		client.API.EnrichPutPolicy.WithContext(ctx),
+		client.API.EnrichPutPolicy.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when add enrich policy %s: %s", name, res.String())
+	}
+
+	d.SetId(name)
+
+	tflog.Info(ctx, "Created enrich policy successfully")
+
+	if d.Get("execute").(bool) {
+		status, err := executeEnrichPolicy(ctx, name, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("last_apply_execution_status", status)
+
+		tflog.Info(ctx, "Executed enrich policy successfully", "phase", status)
+	}
+
+	return resourceElasticsearchEnrichPolicyRead(ctx, d, meta)
+}
+
+// resourceElasticsearchEnrichPolicyRead read existing enrich policy in Elasticsearch
+func resourceElasticsearchEnrichPolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	policyType, policy, err := getEnrichPolicy(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if policy == nil {
+		tflog.Warn(ctx, "Enrich policy not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	tflog.Debug(ctx, "Get enrich policy successfully", "policy_type", policyType)
+
+	d.Set("name", id)
+	d.Set("policy_type", policyType)
+	d.Set("indices", policy.Indices)
+	d.Set("match_field", policy.MatchField)
+	d.Set("enrich_fields", policy.EnrichFields)
+
+	flattenQuery, err := convertInterfaceToJsonString(policy.Query)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("query", flattenQuery)
+
+	// last_apply_execution_status is kept from state, Elasticsearch not keep the execution status
+
+	return nil
+}
+
+// resourceElasticsearchEnrichPolicyImport import existing enrich policy, without executing it
+func resourceElasticsearchEnrichPolicyImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("execute", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceElasticsearchEnrichPolicyDelete delete existing enrich policy in Elasticsearch
+func resourceElasticsearchEnrichPolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.EnrichDeletePolicy(
+		id,
+		client.API.EnrichDeletePolicy.WithContext(ctx),
+		client.API.EnrichDeletePolicy.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Enrich policy not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete enrich policy %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted enrich policy successfully")
+	return nil
+}
+
+// Print EnrichPolicy object as Json string
+func (r EnrichPolicy) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// getEnrichPolicy return the policy type and the enrich policy from Elasticsearch, or nil policy if not found
+func getEnrichPolicy(ctx context.Context, name string, meta interface{}) (string, *EnrichPolicySpec, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.EnrichGetPolicy(
+		client.API.EnrichGetPolicy.WithName(name),
+		client.API.EnrichGetPolicy.WithContext(ctx),
+		client.API.EnrichGetPolicy.WithPretty(),
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return "", nil, nil
+		}
+		return "", nil, errors.Errorf("Error when get enrich policy %s: %s", name, res.String())
+	}
+
+	policies := &EnrichPolicies{}
+	if err := json.NewDecoder(res.Body).Decode(policies); err != nil {
+		return "", nil, err
+	}
+
+	for _, policy := range policies.Policies {
+		for policyType, spec := range policy.Config {
+			if spec != nil && spec.Name == name {
+				return policyType, spec, nil
+			}
+		}
+	}
+
+	return "", nil, nil
+}
+
+// executeEnrichPolicy execute the enrich policy and wait it finish. It return the execution phase
+func executeEnrichPolicy(ctx context.Context, name string, meta interface{}) (string, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.EnrichExecutePolicy(
+		name,
+		client.API.EnrichExecutePolicy.WithWaitForCompletion(true),
+		client.API.EnrichExecutePolicy.WithContext(ctx),
+		client.API.EnrichExecutePolicy.WithPretty(),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return "", errors.Errorf("Error when execute enrich policy %s: %s", name, res.String())
+	}
+
+	execution := &EnrichPolicyExecuteResponse{}
+	if err := json.NewDecoder(res.Body).Decode(execution); err != nil {
+		return "", err
+	}
+	if execution.Status == nil {
+		return "", errors.Errorf("Error when execute enrich policy %s: no status returned", name)
+	}
+
+	return execution.Status.Phase, nil
+}
@@ -0,0 +1,139 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchEnrichPolicy(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchEnrichPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchEnrichPolicy,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchEnrichPolicyExists("elasticsearch_enrich_policy.test"),
+					resource.TestCheckResourceAttr("elasticsearch_enrich_policy.test", "policy_type", "match"),
+					resource.TestCheckResourceAttr("elasticsearch_enrich_policy.test", "last_apply_execution_status", "COMPLETE"),
+				),
+			},
+			{
+				Config: testElasticsearchEnrichPolicyUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchEnrichPolicyExists("elasticsearch_enrich_policy.test"),
+					resource.TestCheckResourceAttr("elasticsearch_enrich_policy.test", "enrich_fields.#", "2"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_enrich_policy.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"execute", "last_apply_execution_status"},
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchEnrichPolicyExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No enrich policy ID is set")
+		}
+
+		_, policy, err := getEnrichPolicy(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if policy == nil {
+			return errors.Errorf("Enrich policy %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchEnrichPolicyDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_enrich_policy" {
+			continue
+		}
+
+		_, policy, err := getEnrichPolicy(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if policy != nil {
+			return fmt.Errorf("Enrich policy %q still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testElasticsearchEnrichPolicy = `
+resource "elasticsearch_index" "test_enrich" {
+  name     = "terraform-test-enrich"
+  mappings = <<EOF
+{
+  "properties": {
+    "email": { "type": "keyword" },
+    "first_name": { "type": "text" },
+    "last_name": { "type": "text" }
+  }
+}
+EOF
+  deletion_protection = false
+}
+
+resource "elasticsearch_enrich_policy" "test" {
+  name          = "terraform-test"
+  policy_type   = "match"
+  indices       = [elasticsearch_index.test_enrich.name]
+  match_field   = "email"
+  enrich_fields = ["first_name"]
+  execute       = true
+}
+`
+
+var testElasticsearchEnrichPolicyUpdate = `
+resource "elasticsearch_index" "test_enrich" {
+  name     = "terraform-test-enrich"
+  mappings = <<EOF
+{
+  "properties": {
+    "email": { "type": "keyword" },
+    "first_name": { "type": "text" },
+    "last_name": { "type": "text" }
+  }
+}
+EOF
+  deletion_protection = false
+}
+
+resource "elasticsearch_enrich_policy" "test" {
+  name          = "terraform-test"
+  policy_type   = "match"
+  indices       = [elasticsearch_index.test_enrich.name]
+  match_field   = "email"
+  enrich_fields = ["first_name", "last_name"]
+  query         = <<EOF
+{
+  "match_all": {}
+}
+EOF
+}
+`
@@ -0,0 +1,595 @@
+// Manage index in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/indices.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// Index is the index object returned by API
+type Index map[string]*IndexSpec
+
+// IndexSpec is the index specification
+type IndexSpec struct {
+	Settings interface{} `json:"settings,omitempty"`
+	Mappings interface{} `json:"mappings,omitempty"`
+	Aliases  interface{} `json:"aliases,omitempty"`
+}
+
+// AliasesActions is the body used to update aliases atomically
+type AliasesActions struct {
+	Actions []map[string]interface{} `json:"actions"`
+}
+
+// staticIndexSettings is the list of index settings that can only be set when create index
+// Key that end with dot is a prefix
+var staticIndexSettings = []string{
+	"index.number_of_shards",
+	"index.number_of_routing_shards",
+	"index.routing_partition_size",
+	"index.codec",
+	"index.soft_deletes.enabled",
+	"index.load_fixed_bitset_filters_eagerly",
+	"index.shard.check_on_startup",
+	"index.store.type",
+	"index.sort.",
+	"index.analysis.",
+	"index.similarity.",
+}
+
+// computedIndexSettings is the list of index settings set by Elasticsearch that are not imported
+// Key that end with dot is a prefix
+var computedIndexSettings = []string{
+	"index.uuid",
+	"index.creation_date",
+	"index.provided_name",
+	"index.version.",
+	"index.routing.allocation.include._tier_preference",
+}
+
+// resourceElasticsearchIndex handle the index API call
+func resourceElasticsearchIndex() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchIndexCreate,
+		UpdateContext: resourceElasticsearchIndexUpdate,
+		ReadContext:   resourceElasticsearchIndexRead,
+		DeleteContext: resourceElasticsearchIndexDelete,
+		CustomizeDiff: resourceElasticsearchIndexCustomizeDiff,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceElasticsearchIndexImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"settings": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressIndexSettings,
+				Description:      "The index settings as JSON object. Only static settings force to recreate the index",
+			},
+			"mappings": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressIndexDotProperties,
+				Description:      "The index mappings as JSON object",
+			},
+			"aliases": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressIndexDotProperties,
+				Description:      "The index aliases as JSON object",
+			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Refuse to delete the index while it's true",
+			},
+		},
+	}
+}
+
+// resourceElasticsearchIndexCreate create index
+func resourceElasticsearchIndexCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	index := &IndexSpec{
+		Settings: optionalInterfaceJSON(d.Get("settings").(string)),
+		Mappings: optionalInterfaceJSON(d.Get("mappings").(string)),
+		Aliases:  optionalInterfaceJSON(d.Get("aliases").(string)),
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.Create(
+		name,
+		client.API.Indices.Create.WithBody(strings.NewReader(index.String())),
+		client.API.Indices.Create.WithContext(ctx),
+		client.API.Indices.Create.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when create index %s: %s", name, res.String())
+	}
+
+	d.SetId(name)
+	tflog.Info(ctx, "Create index successfully")
+
+	return resourceElasticsearchIndexRead(ctx, d, meta)
+}
+
+// resourceElasticsearchIndexUpdate update index settings, mappings and aliases
+func resourceElasticsearchIndexUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	if d.HasChange("settings") {
+		if err := updateIndexSettings(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("mappings") {
+		if err := updateIndexMappings(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("aliases") {
+		if err := updateIndexAliases(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	tflog.Info(ctx, "Update index successfully")
+
+	return resourceElasticsearchIndexRead(ctx, d, meta)
+}
+
+// resourceElasticsearchIndexRead read index
+func resourceElasticsearchIndexRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.Get(
+		[]string{id},
+		client.API.Indices.Get.WithFlatSettings(true),
+		client.API.Indices.Get.WithContext(ctx),
+		client.API.Indices.Get.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Index not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when get index %s: %s", id, res.String())
+	}
+
+	indexes := make(Index)
+	if err := json.NewDecoder(res.Body).Decode(&indexes); err != nil {
+		return diag.FromErr(err)
+	}
+	index, ok := indexes[id]
+	if !ok {
+		tflog.Warn(ctx, "Index not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	// Only keep settings managed by Terraform
+	currentSettings, err := flattenIndexSettingsJSON(d.Get("settings").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	settings := make(map[string]interface{})
+	if index.Settings != nil {
+		for key, value := range index.Settings.(map[string]interface{}) {
+			if _, ok := currentSettings[key]; ok {
+				settings[key] = value
+			}
+		}
+	}
+
+	settingsJSON, err := convertInterfaceToJsonString(settings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Only keep mappings and aliases managed by Terraform, like the fields added by dynamic mapping
+	mappingsJSON, err := filterJSONFromState(d.Get("mappings").(string), index.Mappings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	aliases, err := keepAliasesWriteIndexFromState(d.Get("aliases").(string), index.Aliases)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	aliasesJSON, err := filterJSONFromState(d.Get("aliases").(string), aliases)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Get index successfully", "settings", settingsJSON, "mappings", mappingsJSON, "aliases", aliasesJSON)
+
+	d.Set("name", id)
+	d.Set("settings", settingsJSON)
+	d.Set("mappings", mappingsJSON)
+	d.Set("aliases", aliasesJSON)
+
+	return nil
+}
+
+// resourceElasticsearchIndexDelete delete index
+func resourceElasticsearchIndexDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("Index %s is protected against deletion, set deletion_protection to false and apply it before to destroy it", id)
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.Delete(
+		[]string{id},
+		client.API.Indices.Delete.WithContext(ctx),
+		client.API.Indices.Delete.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Index not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete index %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceElasticsearchIndexImport import index with all its settings, except the settings computed by Elasticsearch, its mappings and its aliases
+// The deletion protection is enabled
+func resourceElasticsearchIndexImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.Get(
+		[]string{id},
+		client.API.Indices.Get.WithFlatSettings(true),
+		client.API.Indices.Get.WithContext(ctx),
+		client.API.Indices.Get.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("Error when get index %s: %s", id, res.String())
+	}
+
+	indexes := make(Index)
+	if err := json.NewDecoder(res.Body).Decode(&indexes); err != nil {
+		return nil, err
+	}
+	index, ok := indexes[id]
+	if !ok {
+		return nil, errors.Errorf("Index %s not found", id)
+	}
+
+	settings := make(map[string]interface{})
+	if index.Settings != nil {
+		for key, value := range index.Settings.(map[string]interface{}) {
+			if !matchIndexSettings(key, computedIndexSettings) {
+				settings[key] = value
+			}
+		}
+	}
+	settingsJSON, err := convertInterfaceToJsonString(settings)
+	if err != nil {
+		return nil, err
+	}
+	mappingsJSON, err := convertInterfaceToJsonString(index.Mappings)
+	if err != nil {
+		return nil, err
+	}
+	aliasesJSON, err := convertInterfaceToJsonString(index.Aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("settings", settingsJSON)
+	d.Set("mappings", mappingsJSON)
+	d.Set("aliases", aliasesJSON)
+	d.Set("deletion_protection", true)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceElasticsearchIndexCustomizeDiff permit to recreate index only when static settings change
+func resourceElasticsearchIndexCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("settings") || !d.NewValueKnown("settings") {
+		return nil
+	}
+
+	o, n := d.GetChange("settings")
+	oldSettings, err := flattenIndexSettingsJSON(o.(string))
+	if err != nil {
+		return err
+	}
+	newSettings, err := flattenIndexSettingsJSON(n.(string))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range diffSettings(oldSettings, newSettings) {
+		if matchIndexSettings(key, staticIndexSettings) {
+			tflog.Info(ctx, "Static index setting changed, index must be recreated", "setting", key)
+			return d.ForceNew("settings")
+		}
+	}
+
+	return nil
+}
+
+// updateIndexSettings update the dynamic settings. Removed settings are reset to default value
+func updateIndexSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	o, n := d.GetChange("settings")
+	oldSettings, err := flattenIndexSettingsJSON(o.(string))
+	if err != nil {
+		return err
+	}
+	newSettings, err := flattenIndexSettingsJSON(n.(string))
+	if err != nil {
+		return err
+	}
+
+	settings := make(map[string]interface{})
+	for _, key := range diffSettings(oldSettings, newSettings) {
+		settings[key] = newSettings[key]
+	}
+	if len(settings) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	tflog.Debug(ctx, "Update index settings", "settings", string(b))
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.PutSettings(
+		bytes.NewReader(b),
+		client.API.Indices.PutSettings.WithIndex(id),
+		client.API.Indices.PutSettings.WithContext(ctx),
+		client.API.Indices.PutSettings.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when update settings on index %s: %s", id, res.String())
+	}
+
+	return nil
+}
+
+// updateIndexMappings update the mappings. Elasticsearch refuse the change that is not compatible with existing mappings
+func updateIndexMappings(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	mappings := d.Get("mappings").(string)
+	if mappings == "" {
+		return nil
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.PutMapping(
+		strings.NewReader(mappings),
+		client.API.Indices.PutMapping.WithIndex(id),
+		client.API.Indices.PutMapping.WithContext(ctx),
+		client.API.Indices.PutMapping.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when update mappings on index %s: %s", id, res.String())
+	}
+
+	return nil
+}
+
+// updateIndexAliases add, update and remove aliases in one atomic call
+func updateIndexAliases(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	o, n := d.GetChange("aliases")
+	oldAliases := make(map[string]interface{})
+	newAliases := make(map[string]interface{})
+	if o.(string) != "" {
+		if err := json.Unmarshal([]byte(o.(string)), &oldAliases); err != nil {
+			return err
+		}
+	}
+	if n.(string) != "" {
+		if err := json.Unmarshal([]byte(n.(string)), &newAliases); err != nil {
+			return err
+		}
+	}
+
+	actions := &AliasesActions{
+		Actions: make([]map[string]interface{}, 0),
+	}
+	for alias := range oldAliases {
+		if _, ok := newAliases[alias]; !ok {
+			actions.Actions = append(actions.Actions, map[string]interface{}{
+				"remove": map[string]interface{}{
+					"index": id,
+					"alias": alias,
+				},
+			})
+		}
+	}
+	for alias, spec := range newAliases {
+		if reflect.DeepEqual(oldAliases[alias], spec) {
+			continue
+		}
+		action := map[string]interface{}{
+			"index": id,
+			"alias": alias,
+		}
+		if spec != nil {
+			for key, value := range spec.(map[string]interface{}) {
+				action[key] = value
+			}
+		}
+		// Not set again the write index when it's not changed, it may be switched by rollover
+		if oldSpec, ok := oldAliases[alias].(map[string]interface{}); ok {
+			if reflect.DeepEqual(oldSpec["is_write_index"], action["is_write_index"]) {
+				delete(action, "is_write_index")
+			}
+		}
+		actions.Actions = append(actions.Actions, map[string]interface{}{
+			"add": action,
+		})
+	}
+	if len(actions.Actions) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(actions)
+	if err != nil {
+		return err
+	}
+	tflog.Debug(ctx, "Update index aliases", "actions", string(b))
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.UpdateAliases(
+		bytes.NewReader(b),
+		client.API.Indices.UpdateAliases.WithContext(ctx),
+		client.API.Indices.UpdateAliases.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when update aliases on index %s: %s", id, res.String())
+	}
+
+	return nil
+}
+
+// keepAliasesWriteIndexFromState permit to keep is_write_index from state on each alias
+// Rollover switch the write index, so it's only set when create the alias or when it's changed
+func keepAliasesWriteIndexFromState(current string, remote interface{}) (interface{}, error) {
+	remoteAliases, ok := remote.(map[string]interface{})
+	if current == "" || !ok {
+		return remote, nil
+	}
+
+	currentAliases := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(current), &currentAliases); err != nil {
+		return nil, err
+	}
+
+	for alias, spec := range currentAliases {
+		currentSpec, ok := spec.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		isWriteIndex, ok := currentSpec["is_write_index"]
+		if !ok {
+			continue
+		}
+		remoteSpec, ok := remoteAliases[alias].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		remoteSpec["is_write_index"] = isWriteIndex
+	}
+
+	return remoteAliases, nil
+}
+
+// Print Index object as Json string
+func (r *IndexSpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// flattenIndexSettingsJSON permit to convert settings as JSON string on flat settings
+func flattenIndexSettingsJSON(settings string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if settings == "" {
+		return data, nil
+	}
+	if err := json.Unmarshal([]byte(settings), &data); err != nil {
+		return nil, err
+	}
+
+	return flattenIndexSettings(data), nil
+}
+
+// flattenIndexSettings permit to convert settings on the same format that Elasticsearch return with flat_settings.
+// All keys are prefixed by `index.` and all values are converted to string
+func flattenIndexSettings(settings map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	flattenSettings("", settings, result)
+
+	for key, value := range result {
+		if !strings.HasPrefix(key, "index.") {
+			delete(result, key)
+			result[fmt.Sprintf("index.%s", key)] = value
+		}
+	}
+
+	return result
+}
+
+// matchIndexSettings return true if key is on the list. Item that end with dot is a prefix
+func matchIndexSettings(key string, list []string) bool {
+	for _, item := range list {
+		if key == item || (strings.HasSuffix(item, ".") && strings.HasPrefix(key, item)) {
+			return true
+		}
+	}
+
+	return false
+}
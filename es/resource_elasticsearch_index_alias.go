@@ -0,0 +1,324 @@
+// Manage alias across indices in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-aliases.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// IndexAliases is the aliases object returned by API for each index
+type IndexAliases map[string]*IndexAliasesSpec
+
+// IndexAliasesSpec is the list of aliases of one index
+type IndexAliasesSpec struct {
+	Aliases map[string]*IndexAliasSpec `json:"aliases"`
+}
+
+// IndexAliasSpec is the alias specification
+type IndexAliasSpec struct {
+	Filter        interface{} `json:"filter,omitempty"`
+	IndexRouting  string      `json:"index_routing,omitempty"`
+	SearchRouting string      `json:"search_routing,omitempty"`
+	IsWriteIndex  *bool       `json:"is_write_index,omitempty"`
+	IsHidden      *bool       `json:"is_hidden,omitempty"`
+}
+
+// resourceElasticsearchIndexAlias handle the alias API call
+func resourceElasticsearchIndexAlias() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchIndexAliasCreate,
+		UpdateContext: resourceElasticsearchIndexAliasUpdate,
+		ReadContext:   resourceElasticsearchIndexAliasRead,
+		DeleteContext: resourceElasticsearchIndexAliasDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"indices": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"filter": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"routing": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"index_routing", "search_routing"},
+			},
+			"index_routing": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"search_routing": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"write_index": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The index of indices where the alias is_write_index is set to true",
+			},
+			"is_hidden": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+// resourceElasticsearchIndexAliasCreate create alias
+func resourceElasticsearchIndexAliasCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	if err := updateIndexAlias(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	tflog.Info(ctx, "Create alias successfully")
+
+	return resourceElasticsearchIndexAliasRead(ctx, d, meta)
+}
+
+// resourceElasticsearchIndexAliasUpdate update alias
+func resourceElasticsearchIndexAliasUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	if err := updateIndexAlias(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "Update alias successfully")
+
+	return resourceElasticsearchIndexAliasRead(ctx, d, meta)
+}
+
+// resourceElasticsearchIndexAliasRead read alias
+func resourceElasticsearchIndexAliasRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.GetAlias(
+		client.API.Indices.GetAlias.WithName(id),
+		client.API.Indices.GetAlias.WithContext(ctx),
+		client.API.Indices.GetAlias.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Alias not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when get alias %s: %s", id, res.String())
+	}
+
+	indexAliases := make(IndexAliases)
+	if err := json.NewDecoder(res.Body).Decode(&indexAliases); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var alias *IndexAliasSpec
+	indices := make([]string, 0, len(indexAliases))
+	writeIndex := ""
+	for index, spec := range indexAliases {
+		if spec == nil || spec.Aliases[id] == nil {
+			continue
+		}
+		indices = append(indices, index)
+		alias = spec.Aliases[id]
+		if spec.Aliases[id].IsWriteIndex != nil && *spec.Aliases[id].IsWriteIndex {
+			writeIndex = index
+		}
+	}
+	if alias == nil {
+		tflog.Warn(ctx, "Alias not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	filter, err := convertInterfaceToJsonString(alias.Filter)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Get alias successfully", "indices", indices, "filter", filter)
+
+	d.Set("name", id)
+	d.Set("indices", indices)
+	d.Set("filter", filter)
+	if d.Get("routing").(string) != "" && alias.IndexRouting == alias.SearchRouting {
+		d.Set("routing", alias.IndexRouting)
+		d.Set("index_routing", "")
+		d.Set("search_routing", "")
+	} else {
+		d.Set("routing", "")
+		d.Set("index_routing", alias.IndexRouting)
+		d.Set("search_routing", alias.SearchRouting)
+	}
+	d.Set("write_index", writeIndex)
+	d.Set("is_hidden", alias.IsHidden != nil && *alias.IsHidden)
+
+	return nil
+}
+
+// resourceElasticsearchIndexAliasDelete delete alias from its indices
+func resourceElasticsearchIndexAliasDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	// Remove alias from indices on state, wildcard not match hidden and closed indices
+	actions := &AliasesActions{
+		Actions: []map[string]interface{}{
+			{
+				"remove": map[string]interface{}{
+					"indices":    convertArrayInterfaceToArrayString(d.Get("indices").(*schema.Set).List()),
+					"alias":      id,
+					"must_exist": false,
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(actions)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.UpdateAliases(
+		bytes.NewReader(b),
+		client.API.Indices.UpdateAliases.WithContext(ctx),
+		client.API.Indices.UpdateAliases.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if res.StatusCode == 404 && getErrorType(body) == "aliases_not_found_exception" {
+			tflog.Warn(ctx, "Alias not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete alias %s: [%s] %s", id, res.Status(), string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// updateIndexAlias add alias on all indices and remove it from indices not in list, in one atomic call
+func updateIndexAlias(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("name").(string)
+	writeIndex := d.Get("write_index").(string)
+
+	o, n := d.GetChange("indices")
+	oldIndices := o.(*schema.Set)
+	newIndices := n.(*schema.Set)
+	if writeIndex != "" && !newIndices.Contains(writeIndex) {
+		return errors.Errorf("The write_index %s must be on indices", writeIndex)
+	}
+
+	actions := &AliasesActions{
+		Actions: make([]map[string]interface{}, 0),
+	}
+	removedIndices := convertArrayInterfaceToArrayString(oldIndices.Difference(newIndices).List())
+	if len(removedIndices) > 0 {
+		actions.Actions = append(actions.Actions, map[string]interface{}{
+			"remove": map[string]interface{}{
+				"indices":    removedIndices,
+				"alias":      name,
+				"must_exist": false,
+			},
+		})
+	}
+
+	for _, index := range convertArrayInterfaceToArrayString(newIndices.List()) {
+		action := map[string]interface{}{
+			"index": index,
+			"alias": name,
+		}
+		if d.Get("is_hidden").(bool) || d.HasChange("is_hidden") {
+			action["is_hidden"] = d.Get("is_hidden").(bool)
+		}
+		if filter := optionalInterfaceJSON(d.Get("filter").(string)); filter != nil {
+			action["filter"] = filter
+		}
+		if routing := d.Get("routing").(string); routing != "" {
+			action["routing"] = routing
+		}
+		if indexRouting := d.Get("index_routing").(string); indexRouting != "" {
+			action["index_routing"] = indexRouting
+		}
+		if searchRouting := d.Get("search_routing").(string); searchRouting != "" {
+			action["search_routing"] = searchRouting
+		}
+		if writeIndex != "" {
+			action["is_write_index"] = index == writeIndex
+		}
+
+		actions.Actions = append(actions.Actions, map[string]interface{}{
+			"add": action,
+		})
+	}
+
+	b, err := json.Marshal(actions)
+	if err != nil {
+		return err
+	}
+	tflog.Debug(ctx, "Update alias", "actions", string(b))
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Indices.UpdateAliases(
+		bytes.NewReader(b),
+		client.API.Indices.UpdateAliases.WithContext(ctx),
+		client.API.Indices.UpdateAliases.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when update alias %s: %s", name, res.String())
+	}
+
+	return nil
+}
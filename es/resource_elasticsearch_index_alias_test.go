@@ -0,0 +1,193 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchIndexAlias(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchIndexAliasDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchIndexAlias,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchIndexAliasExists("elasticsearch_index_alias.test"),
+				),
+			},
+			{
+				Config: testElasticsearchIndexAliasUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchIndexAliasExists("elasticsearch_index_alias.test"),
+					resource.TestCheckResourceAttr("elasticsearch_index_alias.test", "indices.#", "2"),
+					resource.TestCheckResourceAttr("elasticsearch_index_alias.test", "write_index", "terraform-test-alias-000002"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_index_alias.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestIndexAliasDeleteFromStateIndices(t *testing.T) {
+	tests := []struct {
+		status   int
+		response string
+		deleted  bool
+		failed   bool
+	}{
+		{200, `{"acknowledged": true}`, true, false},
+		{404, `{"error": {"type": "aliases_not_found_exception", "reason": "aliases [terraform-test] missing"}, "status": 404}`, true, false},
+		{404, `{"error": {"type": "index_not_found_exception", "reason": "no such index [terraform-test-1]"}, "status": 404}`, false, true},
+	}
+
+	for _, test := range tests {
+		var actions *AliasesActions
+		meta, server := newTestProviderMeta(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" || r.URL.Path != "/_aliases" {
+				t.Errorf("Alias must be removed with POST /_aliases, got %s %s", r.Method, r.URL.Path)
+			}
+			actions = &AliasesActions{}
+			json.NewDecoder(r.Body).Decode(actions)
+			w.WriteHeader(test.status)
+			w.Write([]byte(test.response))
+		})
+
+		d := resourceElasticsearchIndexAlias().TestResourceData()
+		d.SetId("terraform-test")
+		d.Set("indices", []interface{}{"terraform-test-1", "terraform-test-2"})
+
+		diags := resourceElasticsearchIndexAliasDelete(context.Background(), d, meta)
+		server.Close()
+
+		expected := []map[string]interface{}{
+			{
+				"remove": map[string]interface{}{
+					"indices":    []interface{}{"terraform-test-1", "terraform-test-2"},
+					"alias":      "terraform-test",
+					"must_exist": false,
+				},
+			},
+		}
+		if actions == nil || !reflect.DeepEqual(actions.Actions, expected) {
+			t.Errorf("Alias must be removed from indices on state, got %+v", actions)
+		}
+		if diags.HasError() != test.failed {
+			t.Errorf("Response %s must failed: %t, got %+v", test.response, test.failed, diags)
+		}
+		if (d.Id() == "") != test.deleted {
+			t.Errorf("Response %s must remove alias from state: %t", test.response, test.deleted)
+		}
+	}
+}
+
+func testCheckElasticsearchIndexAliasExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No alias ID is set")
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Indices.GetAlias(
+			client.API.Indices.GetAlias.WithName(rs.Primary.ID),
+			client.API.Indices.GetAlias.WithContext(context.Background()),
+			client.API.Indices.GetAlias.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when get alias %s: %s", rs.Primary.ID, res.String())
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchIndexAliasDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_index_alias" {
+			continue
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Indices.GetAlias(
+			client.API.Indices.GetAlias.WithName(rs.Primary.ID),
+			client.API.Indices.GetAlias.WithContext(context.Background()),
+			client.API.Indices.GetAlias.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			if res.StatusCode == 404 {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Alias %q still exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+var testElasticsearchIndexAliasIndices = `
+resource "elasticsearch_index" "test1" {
+  name                = "terraform-test-alias-000001"
+  deletion_protection = false
+}
+
+resource "elasticsearch_index" "test2" {
+  name                = "terraform-test-alias-000002"
+  deletion_protection = false
+}
+`
+
+var testElasticsearchIndexAlias = testElasticsearchIndexAliasIndices + `
+resource "elasticsearch_index_alias" "test" {
+  name    = "terraform-test-alias"
+  indices = [elasticsearch_index.test1.name]
+  routing = "1"
+  filter  = <<EOF
+{
+	"term": {
+		"user.id": "test"
+	}
+}
+EOF
+}
+`
+
+var testElasticsearchIndexAliasUpdate = testElasticsearchIndexAliasIndices + `
+resource "elasticsearch_index_alias" "test" {
+  name        = "terraform-test-alias"
+  indices     = [elasticsearch_index.test1.name, elasticsearch_index.test2.name]
+  write_index = elasticsearch_index.test2.name
+}
+`
@@ -2,33 +2,34 @@
 // API documentation:https://www.elastic.co/guide/en/elasticsearch/reference/master/indices-component-template.html
 // Supported version:
 //  - v7
+//  - v8
 
 package es
 
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"strings"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	oelastic "github.com/olivere/elastic/v7"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // resourceElasticsearchIndexComponentTemplate handle the index component template API call
 func resourceElasticsearchIndexComponentTemplate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchIndexComponentTemplateCreate,
-		Update: resourceElasticsearchIndexComponentTemplateUpdate,
-		Read:   resourceElasticsearchIndexComponentTemplateRead,
-		Delete: resourceElasticsearchIndexComponentTemplateDelete,
+		CreateContext: resourceElasticsearchIndexComponentTemplateCreate,
+		UpdateContext: resourceElasticsearchIndexComponentTemplateUpdate,
+		ReadContext:   resourceElasticsearchIndexComponentTemplateRead,
+		DeleteContext: resourceElasticsearchIndexComponentTemplateDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -47,102 +48,104 @@ func resourceElasticsearchIndexComponentTemplate() *schema.Resource {
 }
 
 // resourceElasticsearchIndexComponentTemplateCreate create index component template
-func resourceElasticsearchIndexComponentTemplateCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexComponentTemplateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Get("name").(string))
 
-	err := createIndexComponentTemplate(d, meta)
+	err := createIndexComponentTemplate(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(d.Get("name").(string))
-	return resourceElasticsearchIndexComponentTemplateRead(d, meta)
+	return resourceElasticsearchIndexComponentTemplateRead(ctx, d, meta)
 }
 
 // resourceElasticsearchIndexComponentTemplateUpdate update index component template
-func resourceElasticsearchIndexComponentTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createIndexComponentTemplate(d, meta)
+func resourceElasticsearchIndexComponentTemplateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createIndexComponentTemplate(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceElasticsearchIndexComponentTemplateRead(d, meta)
+	return resourceElasticsearchIndexComponentTemplateRead(ctx, d, meta)
 }
 
 // resourceElasticsearchIndexComponentTemplateRead read index component template
-func resourceElasticsearchIndexComponentTemplateRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexComponentTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Cluster.GetComponentTemplate(
 		client.API.Cluster.GetComponentTemplate.WithName(id),
-		client.API.Cluster.GetComponentTemplate.WithContext(context.Background()),
+		client.API.Cluster.GetComponentTemplate.WithContext(ctx),
 		client.API.Cluster.GetComponentTemplate.WithPretty(),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Index component template %s not found - removing from state", id)
-			log.Warnf("Index component template %s not found - removing from state", id)
+			tflog.Warn(ctx, "Index component template not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get index component template %s: %s", id, res.String())
+		return diag.Errorf("Error when get index component template %s: %s", id, res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	indexComponentTemplateResp := &oelastic.IndicesGetComponentTemplateResponse{}
 	if err := json.Unmarshal(b, indexComponentTemplateResp); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if len(indexComponentTemplateResp.ComponentTemplates) == 0 {
-		fmt.Printf("[WARN] Index component template %s not found - removing from state", id)
-		log.Warnf("Index component template %s not found - removing from state", id)
+		tflog.Warn(ctx, "Index component template not found - removing from state")
 		d.SetId("")
 		return nil
 	}
 
 	indexComponentTemplateJSON, err := json.Marshal(indexComponentTemplateResp.ComponentTemplates[0].ComponentTemplate)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get index component template %s successfully:%+v", id, string(indexComponentTemplateJSON))
+	tflog.Debug(ctx, "Get index component template successfully", "template", string(indexComponentTemplateJSON))
 	d.Set("name", d.Id())
 	d.Set("template", string(indexComponentTemplateJSON))
 	return nil
 }
 
 // resourceElasticsearchIndexComponentTemplateDelete delete index template
-func resourceElasticsearchIndexComponentTemplateDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexComponentTemplateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Cluster.DeleteComponentTemplate(
 		id,
-		client.API.Cluster.DeleteComponentTemplate.WithContext(context.Background()),
+		client.API.Cluster.DeleteComponentTemplate.WithContext(ctx),
 		client.API.Cluster.DeleteComponentTemplate.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Index component template %s not found - removing from state", id)
-			log.Warnf("Index component template %s not found - removing from state", id)
+			tflog.Warn(ctx, "Index component template not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when delete index component template %s: %s", id, res.String())
+		return diag.Errorf("Error when delete index component template %s: %s", id, res.String())
 
 	}
 
@@ -151,15 +154,15 @@ func resourceElasticsearchIndexComponentTemplateDelete(d *schema.ResourceData, m
 }
 
 // createIndexComponentTemplate create or update index component template
-func createIndexComponentTemplate(d *schema.ResourceData, meta interface{}) error {
+func createIndexComponentTemplate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	template := d.Get("template").(string)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Cluster.PutComponentTemplate(
 		name,
 		strings.NewReader(template),
-		client.API.Cluster.PutComponentTemplate.WithContext(context.Background()),
+		client.API.Cluster.PutComponentTemplate.WithContext(ctx),
 		client.API.Cluster.PutComponentTemplate.WithPretty(),
 	)
 
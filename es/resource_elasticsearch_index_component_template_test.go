@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"testing"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -54,7 +52,7 @@ func testCheckElasticsearchIndexComponentTemplateExists(name string) resource.Te
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Cluster.GetComponentTemplate(
 			client.API.Cluster.GetComponentTemplate.WithName(rs.Primary.ID),
 			client.API.Cluster.GetComponentTemplate.WithContext(context.Background()),
@@ -80,7 +78,7 @@ func testCheckElasticsearchIndexComponentTemplateDestroy(s *terraform.State) err
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Cluster.DeleteComponentTemplate(
 			rs.Primary.ID,
 			client.API.Cluster.DeleteComponentTemplate.WithContext(context.Background()),
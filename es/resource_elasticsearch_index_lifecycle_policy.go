@@ -3,32 +3,33 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"strings"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // resourceElasticsearchIndexLifecyclePolicy handle the index lifecycle policy API call
 func resourceElasticsearchIndexLifecyclePolicy() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchIndexLifecyclePolicyCreate,
-		Read:   resourceElasticsearchIndexLifecyclePolicyRead,
-		Update: resourceElasticsearchIndexLifecyclePolicyUpdate,
-		Delete: resourceElasticsearchIndexLifecyclePolicyDelete,
+		CreateContext: resourceElasticsearchIndexLifecyclePolicyCreate,
+		ReadContext:   resourceElasticsearchIndexLifecyclePolicyRead,
+		UpdateContext: resourceElasticsearchIndexLifecyclePolicyUpdate,
+		DeleteContext: resourceElasticsearchIndexLifecyclePolicyDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -47,101 +48,103 @@ func resourceElasticsearchIndexLifecyclePolicy() *schema.Resource {
 }
 
 // resourceElasticsearchIndexLifecyclePolicyCreate create new index lifecycle policy
-func resourceElasticsearchIndexLifecyclePolicyCreate(d *schema.ResourceData, meta interface{}) error {
-	err := createIndexLifecyclePolicy(d, meta)
+func resourceElasticsearchIndexLifecyclePolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Get("name").(string))
+
+	err := createIndexLifecyclePolicy(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(d.Get("name").(string))
-	return resourceElasticsearchIndexLifecyclePolicyRead(d, meta)
+	return resourceElasticsearchIndexLifecyclePolicyRead(ctx, d, meta)
 }
 
 // resourceElasticsearchIndexLifecyclePolicyUpdate update index lifecycle policy
-func resourceElasticsearchIndexLifecyclePolicyUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createIndexLifecyclePolicy(d, meta)
+func resourceElasticsearchIndexLifecyclePolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createIndexLifecyclePolicy(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceElasticsearchIndexLifecyclePolicyRead(d, meta)
+	return resourceElasticsearchIndexLifecyclePolicyRead(ctx, d, meta)
 }
 
 // resourceElasticsearchIndexLifecyclePolicyRead read index lifecycle policy
-func resourceElasticsearchIndexLifecyclePolicyRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexLifecyclePolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.ILM.GetLifecycle(
-		client.API.ILM.GetLifecycle.WithContext(context.Background()),
+		client.API.ILM.GetLifecycle.WithContext(ctx),
 		client.API.ILM.GetLifecycle.WithPretty(),
 		client.API.ILM.GetLifecycle.WithPolicy(id),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Index lifecycle policy %s not found - removing from state", id)
-			log.Warnf("Index lifecycle policy %s not found - removing from state", id)
+			tflog.Warn(ctx, "Index lifecycle policy not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get lifecycle policy %s: %s", id, res.String())
+		return diag.Errorf("Error when get lifecycle policy %s: %s", id, res.String())
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get life cycle policy %s successfully:\n%s", id, string(b))
+	tflog.Debug(ctx, "Get life cycle policy successfully", "body", string(b))
 
 	policyTemp := make(map[string]interface{})
 	err = json.Unmarshal(b, &policyTemp)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	policy := policyTemp[id].(map[string]interface{})["policy"]
 	policyTemp = map[string]interface{}{
 		"policy": policy,
 	}
 
-	log.Debugf("Policy : %+v", policyTemp)
-
 	d.Set("name", id)
 
 	flattenPolicy, err := convertInterfaceToJsonString(policyTemp)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("policy", flattenPolicy)
 	return nil
 }
 
 // resourceElasticsearchIndexLifecyclePolicyDelete delete index lifecycle policy
-func resourceElasticsearchIndexLifecyclePolicyDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexLifecyclePolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.ILM.DeleteLifecycle(
 		id,
-		client.API.ILM.DeleteLifecycle.WithContext(context.Background()),
+		client.API.ILM.DeleteLifecycle.WithContext(ctx),
 		client.API.ILM.DeleteLifecycle.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Index lifecycle policy %s not found - removing from state", id)
-			log.Warnf("Index lifecycle policy %s not found - removing from state", id)
+			tflog.Warn(ctx, "Index lifecycle policy not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when delete lifecycle policy %s: %s", id, res.String())
+		return diag.Errorf("Error when delete lifecycle policy %s: %s", id, res.String())
 	}
 
 	d.SetId("")
@@ -149,14 +152,14 @@ func resourceElasticsearchIndexLifecyclePolicyDelete(d *schema.ResourceData, met
 }
 
 // createIndexLifecyclePolicy create or update index lifecycle policy
-func createIndexLifecyclePolicy(d *schema.ResourceData, meta interface{}) error {
+func createIndexLifecyclePolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	policy := d.Get("policy").(string)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.ILM.PutLifecycle(
 		name,
-		client.API.ILM.PutLifecycle.WithContext(context.Background()),
+		client.API.ILM.PutLifecycle.WithContext(ctx),
 		client.API.ILM.PutLifecycle.WithPretty(),
 		client.API.ILM.PutLifecycle.WithBody(strings.NewReader(policy)),
 	)
@@ -2,33 +2,34 @@
 // API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/index-templates.html
 // Supported version:
 //  - v7
+//  - v8
 
 package es
 
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"strings"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	olivere "github.com/olivere/elastic/v7"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // resourceElasticsearchIndexTemplate handle the index template API call
 func resourceElasticsearchIndexTemplate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchIndexTemplateCreate,
-		Update: resourceElasticsearchIndexTemplateUpdate,
-		Read:   resourceElasticsearchIndexTemplateRead,
-		Delete: resourceElasticsearchIndexTemplateDelete,
+		CreateContext: resourceElasticsearchIndexTemplateCreate,
+		UpdateContext: resourceElasticsearchIndexTemplateUpdate,
+		ReadContext:   resourceElasticsearchIndexTemplateRead,
+		DeleteContext: resourceElasticsearchIndexTemplateDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -47,102 +48,104 @@ func resourceElasticsearchIndexTemplate() *schema.Resource {
 }
 
 // resourceElasticsearchIndexTemplateCreate create index template
-func resourceElasticsearchIndexTemplateCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexTemplateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Get("name").(string))
 
-	err := createIndexTemplate(d, meta)
+	err := createIndexTemplate(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(d.Get("name").(string))
-	return resourceElasticsearchIndexTemplateRead(d, meta)
+	return resourceElasticsearchIndexTemplateRead(ctx, d, meta)
 }
 
 // resourceElasticsearchIndexTemplateUpdate update index template
-func resourceElasticsearchIndexTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createIndexTemplate(d, meta)
+func resourceElasticsearchIndexTemplateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createIndexTemplate(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceElasticsearchIndexTemplateRead(d, meta)
+	return resourceElasticsearchIndexTemplateRead(ctx, d, meta)
 }
 
 // resourceElasticsearchIndexTemplateRead read index template
-func resourceElasticsearchIndexTemplateRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Indices.GetIndexTemplate(
 		client.API.Indices.GetIndexTemplate.WithName(id),
-		client.API.Indices.GetIndexTemplate.WithContext(context.Background()),
+		client.API.Indices.GetIndexTemplate.WithContext(ctx),
 		client.API.Indices.GetIndexTemplate.WithPretty(),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Index template %s not found - removing from state", id)
-			log.Warnf("Index template %s not found - removing from state", id)
+			tflog.Warn(ctx, "Index template not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get index template %s: %s", id, res.String())
+		return diag.Errorf("Error when get index template %s: %s", id, res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	indexTemplate := &olivere.IndicesGetIndexTemplateResponse{}
 	if err := json.Unmarshal(b, indexTemplate); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if len(indexTemplate.IndexTemplates) == 0 {
-		fmt.Printf("[WARN] Index template %s not found - removing from state", id)
-		log.Warnf("Index template %s not found - removing from state", id)
+		tflog.Warn(ctx, "Index template not found - removing from state")
 		d.SetId("")
 		return nil
 	}
 
 	indexTemplateJSON, err := json.Marshal(indexTemplate.IndexTemplates[0].IndexTemplate)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get index template %s successfully:%+v", id, string(indexTemplateJSON))
+	tflog.Debug(ctx, "Get index template successfully", "template", string(indexTemplateJSON))
 	d.Set("name", d.Id())
 	d.Set("template", string(indexTemplateJSON))
 	return nil
 }
 
 // resourceElasticsearchIndexTemplateDelete delete index template
-func resourceElasticsearchIndexTemplateDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexTemplateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Indices.DeleteIndexTemplate(
 		id,
-		client.API.Indices.DeleteIndexTemplate.WithContext(context.Background()),
+		client.API.Indices.DeleteIndexTemplate.WithContext(ctx),
 		client.API.Indices.DeleteIndexTemplate.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Index template %s not found - removing from state", id)
-			log.Warnf("Index template %s not found - removing from state", id)
+			tflog.Warn(ctx, "Index template not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when delete index template %s: %s", id, res.String())
+		return diag.Errorf("Error when delete index template %s: %s", id, res.String())
 
 	}
 
@@ -151,15 +154,15 @@ func resourceElasticsearchIndexTemplateDelete(d *schema.ResourceData, meta inter
 }
 
 // createIndexTemplate create or update index template
-func createIndexTemplate(d *schema.ResourceData, meta interface{}) error {
+func createIndexTemplate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	template := d.Get("template").(string)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Indices.PutIndexTemplate(
 		name,
 		strings.NewReader(template),
-		client.API.Indices.PutIndexTemplate.WithContext(context.Background()),
+		client.API.Indices.PutIndexTemplate.WithContext(ctx),
 		client.API.Indices.PutIndexTemplate.WithPretty(),
 	)
 
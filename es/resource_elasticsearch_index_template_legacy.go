@@ -3,33 +3,34 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"strings"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	olivere "github.com/olivere/elastic/v7"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // resourceElasticsearchIndexTemplateLegacy handle the index template API call
 func resourceElasticsearchIndexTemplateLegacy() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchIndexTemplateLegacyCreate,
-		Update: resourceElasticsearchIndexTemplateLegacyUpdate,
-		Read:   resourceElasticsearchIndexTemplateLegacyRead,
-		Delete: resourceElasticsearchIndexTemplateLegacyDelete,
+		CreateContext: resourceElasticsearchIndexTemplateLegacyCreate,
+		UpdateContext: resourceElasticsearchIndexTemplateLegacyUpdate,
+		ReadContext:   resourceElasticsearchIndexTemplateLegacyRead,
+		DeleteContext: resourceElasticsearchIndexTemplateLegacyDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -48,65 +49,68 @@ func resourceElasticsearchIndexTemplateLegacy() *schema.Resource {
 }
 
 // resourceElasticsearchIndexTemplateLegacyCreate create index template
-func resourceElasticsearchIndexTemplateLegacyCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexTemplateLegacyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Get("name").(string))
 
-	err := createIndexTemplateLegacy(d, meta)
+	err := createIndexTemplateLegacy(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(d.Get("name").(string))
-	return resourceElasticsearchIndexTemplateLegacyRead(d, meta)
+	return resourceElasticsearchIndexTemplateLegacyRead(ctx, d, meta)
 }
 
 // resourceElasticsearchIndexTemplateLegacyUpdate update index template
-func resourceElasticsearchIndexTemplateLegacyUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createIndexTemplateLegacy(d, meta)
+func resourceElasticsearchIndexTemplateLegacyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createIndexTemplateLegacy(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceElasticsearchIndexTemplateLegacyRead(d, meta)
+	return resourceElasticsearchIndexTemplateLegacyRead(ctx, d, meta)
 }
 
 // resourceElasticsearchIndexTemplateLegacyRead read index template
-func resourceElasticsearchIndexTemplateLegacyRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexTemplateLegacyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Indices.GetTemplate(
 		client.API.Indices.GetTemplate.WithName(id),
-		client.API.Indices.GetTemplate.WithContext(context.Background()),
+		client.API.Indices.GetTemplate.WithContext(ctx),
 		client.API.Indices.GetTemplate.WithPretty(),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Index template %s not found - removing from state", id)
-			log.Warnf("Index template %s not found - removing from state", id)
+			tflog.Warn(ctx, "Index template not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get index template %s: %s", id, res.String())
+		return diag.Errorf("Error when get index template %s: %s", id, res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	indexTemplate := make(map[string]*olivere.IndicesGetTemplateResponse)
 	if err := json.Unmarshal(b, &indexTemplate); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	indexTemplateJSON, err := json.Marshal(indexTemplate[id])
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get index template %s successfully:%+v", id, string(indexTemplateJSON))
+	tflog.Debug(ctx, "Get index template successfully", "template", string(indexTemplateJSON))
 	d.Set("name", d.Id())
 	d.Set("template", string(indexTemplateJSON))
 	return nil
@@ -114,31 +118,31 @@ func resourceElasticsearchIndexTemplateLegacyRead(d *schema.ResourceData, meta i
 }
 
 // resourceElasticsearchIndexTemplateLegacyDelete delete index template
-func resourceElasticsearchIndexTemplateLegacyDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchIndexTemplateLegacyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Indices.DeleteTemplate(
 		id,
-		client.API.Indices.DeleteTemplate.WithContext(context.Background()),
+		client.API.Indices.DeleteTemplate.WithContext(ctx),
 		client.API.Indices.DeleteTemplate.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Index template %s not found - removing from state", id)
-			log.Warnf("Index template %s not found - removing from state", id)
+			tflog.Warn(ctx, "Index template not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when delete index template %s: %s", id, res.String())
+		return diag.Errorf("Error when delete index template %s: %s", id, res.String())
 
 	}
 
@@ -147,15 +151,15 @@ func resourceElasticsearchIndexTemplateLegacyDelete(d *schema.ResourceData, meta
 }
 
 // createIndexTemplateLegacy create or update index template
-func createIndexTemplateLegacy(d *schema.ResourceData, meta interface{}) error {
+func createIndexTemplateLegacy(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	template := d.Get("template").(string)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Indices.PutTemplate(
 		name,
 		strings.NewReader(template),
-		client.API.Indices.PutTemplate.WithContext(context.Background()),
+		client.API.Indices.PutTemplate.WithContext(ctx),
 		client.API.Indices.PutTemplate.WithPretty(),
 	)
 
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"testing"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -54,7 +52,7 @@ func testCheckElasticsearchIndexTemplateLegacyExists(name string) resource.TestC
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Indices.GetTemplate(
 			client.API.Indices.GetTemplate.WithName(rs.Primary.ID),
 			client.API.Indices.GetTemplate.WithContext(context.Background()),
@@ -80,7 +78,7 @@ func testCheckElasticsearchIndexTemplateLegacyDestroy(s *terraform.State) error
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Indices.DeleteTemplate(
 			rs.Primary.ID,
 			client.API.Indices.DeleteTemplate.WithContext(context.Background()),
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"testing"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -54,7 +52,7 @@ func testCheckElasticsearchIndexTemplateExists(name string) resource.TestCheckFu
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Indices.GetIndexTemplate(
 			client.API.Indices.GetIndexTemplate.WithName(rs.Primary.ID),
 			client.API.Indices.GetIndexTemplate.WithContext(context.Background()),
@@ -80,7 +78,7 @@ func testCheckElasticsearchIndexTemplateDestroy(s *terraform.State) error {
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Indices.DeleteIndexTemplate(
 			rs.Primary.ID,
 			client.API.Indices.DeleteIndexTemplate.WithContext(context.Background()),
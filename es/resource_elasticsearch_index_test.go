@@ -0,0 +1,283 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchIndex(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchIndex,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchIndexExists("elasticsearch_index.test"),
+				),
+			},
+			{
+				Config: testElasticsearchIndexUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchIndexExists("elasticsearch_index.test"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_index.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"settings", "deletion_protection"},
+			},
+		},
+	})
+}
+
+func TestFlattenIndexSettings(t *testing.T) {
+	settings := map[string]interface{}{
+		"number_of_shards": float64(1),
+		"index": map[string]interface{}{
+			"refresh_interval": "5s",
+			"analysis.analyzer.test.filter": []interface{}{
+				"lowercase",
+			},
+		},
+		"index.blocks.read_only": true,
+	}
+	expected := map[string]interface{}{
+		"index.number_of_shards":              "1",
+		"index.refresh_interval":              "5s",
+		"index.analysis.analyzer.test.filter": []interface{}{"lowercase"},
+		"index.blocks.read_only":              "true",
+	}
+
+	result := flattenIndexSettings(settings)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Settings must be flattened as %+v, got %+v", expected, result)
+	}
+
+	if !matchIndexSettings("index.number_of_shards", staticIndexSettings) {
+		t.Errorf("index.number_of_shards must be static setting")
+	}
+	if !matchIndexSettings("index.analysis.analyzer.test.filter", staticIndexSettings) {
+		t.Errorf("index.analysis.* must be static setting")
+	}
+	if matchIndexSettings("index.number_of_replicas", staticIndexSettings) {
+		t.Errorf("index.number_of_replicas must be dynamic setting")
+	}
+}
+
+func TestKeepAliasesWriteIndexFromState(t *testing.T) {
+	// Write index switched by rollover
+	remote := map[string]interface{}{
+		"logs": map[string]interface{}{
+			"is_write_index": false,
+		},
+		"other": map[string]interface{}{},
+	}
+	expected := map[string]interface{}{
+		"logs": map[string]interface{}{
+			"is_write_index": true,
+		},
+		"other": map[string]interface{}{},
+	}
+
+	result, err := keepAliasesWriteIndexFromState(`{"logs": {"is_write_index": true}, "other": {}}`, remote)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Write index must be kept from state as %+v, got %+v", expected, result)
+	}
+
+	// Nothing to keep on import
+	result, err = keepAliasesWriteIndexFromState("", remote)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(result, remote) {
+		t.Errorf("Aliases must not be changed when state is empty, got %+v", result)
+	}
+}
+
+func TestFilterJSONFromState(t *testing.T) {
+	remote := map[string]interface{}{
+		"dynamic": "true",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{
+				"type": "text",
+				"fields": map[string]interface{}{
+					"keyword": map[string]interface{}{
+						"type":         "keyword",
+						"ignore_above": 256,
+					},
+				},
+			},
+		},
+		"dynamic_templates": []interface{}{
+			map[string]interface{}{
+				"strings": map[string]interface{}{
+					"match_mapping_type": "string",
+					"mapping":            map[string]interface{}{"type": "keyword"},
+				},
+			},
+		},
+	}
+
+	result, err := filterJSONFromState(`{"properties": {"name": {"type": "text"}}, "dynamic_templates": [{"strings": {"match_mapping_type": "string"}}]}`, remote)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if result != `{"dynamic_templates":[{"strings":{"match_mapping_type":"string"}}],"properties":{"name":{"type":"text"}}}` {
+		t.Errorf("Only keys set on state must be kept, got %s", result)
+	}
+
+	result, err = filterJSONFromState(`{"dynamic_templates": [{"strings": {"match_mapping_type": "string"}}, {"longs": {"match_mapping_type": "long"}}]}`, remote)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if result != `{"dynamic_templates":[{"strings":{"mapping":{"type":"keyword"},"match_mapping_type":"string"}}]}` {
+		t.Errorf("Array with different length must be kept from API, got %s", result)
+	}
+
+	result, err = filterJSONFromState("", remote)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if result != "" {
+		t.Errorf("Nothing must be returned when state is empty, got %s", result)
+	}
+}
+
+func testCheckElasticsearchIndexExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No index ID is set")
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Indices.Get(
+			[]string{rs.Primary.ID},
+			client.API.Indices.Get.WithContext(context.Background()),
+			client.API.Indices.Get.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when get index %s: %s", rs.Primary.ID, res.String())
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchIndexDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_index" {
+			continue
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Indices.Get(
+			[]string{rs.Primary.ID},
+			client.API.Indices.Get.WithContext(context.Background()),
+			client.API.Indices.Get.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			if res.StatusCode == 404 {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Index %q still exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+var testElasticsearchIndex = `
+resource "elasticsearch_index" "test" {
+  name                = "terraform-test-index-000001"
+  deletion_protection = false
+  settings            = <<EOF
+{
+	"index.number_of_shards": 1,
+	"index.number_of_replicas": 0,
+	"index.refresh_interval": "5s"
+}
+EOF
+  mappings            = <<EOF
+{
+	"properties": {
+		"message": {
+			"type": "text"
+		}
+	}
+}
+EOF
+  aliases             = <<EOF
+{
+	"terraform-test-index": {
+		"is_write_index": true
+	}
+}
+EOF
+}
+`
+
+var testElasticsearchIndexUpdate = `
+resource "elasticsearch_index" "test" {
+  name                = "terraform-test-index-000001"
+  deletion_protection = false
+  settings            = <<EOF
+{
+	"index.number_of_shards": 1,
+	"index.number_of_replicas": 0,
+	"index.refresh_interval": "3s"
+}
+EOF
+  mappings            = <<EOF
+{
+	"properties": {
+		"message": {
+			"type": "text"
+		},
+		"host": {
+			"type": "keyword"
+		}
+	}
+}
+EOF
+  aliases             = <<EOF
+{
+	"terraform-test-index": {
+		"is_write_index": true
+	},
+	"terraform-test-index-read": {}
+}
+EOF
+}
+`
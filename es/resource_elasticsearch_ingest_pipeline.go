@@ -0,0 +1,255 @@
+// Manage ingest pipeline in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/ingest-apis.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// IngestPipeline is the ingest pipeline object returned by API
+type IngestPipeline map[string]*IngestPipelineSpec
+
+// IngestPipelineSpec is the ingest pipeline specification
+type IngestPipelineSpec struct {
+	Description string      `json:"description,omitempty"`
+	Processors  interface{} `json:"processors"`
+	OnFailure   interface{} `json:"on_failure,omitempty"`
+	Version     int         `json:"version,omitempty"`
+	Metadata    interface{} `json:"_meta,omitempty"`
+}
+
+// resourceElasticsearchIngestPipeline handle the ingest pipeline API call
+func resourceElasticsearchIngestPipeline() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchIngestPipelineCreate,
+		ReadContext:   resourceElasticsearchIngestPipelineRead,
+		UpdateContext: resourceElasticsearchIngestPipelineUpdate,
+		DeleteContext: resourceElasticsearchIngestPipelineDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"processors": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"on_failure": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"metadata": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+		},
+	}
+}
+
+// resourceElasticsearchIngestPipelineCreate create new ingest pipeline in Elasticsearch
+func resourceElasticsearchIngestPipelineCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	err := createIngestPipeline(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(name)
+
+	tflog.Info(ctx, "Created ingest pipeline successfully")
+
+	return resourceElasticsearchIngestPipelineRead(ctx, d, meta)
+}
+
+// resourceElasticsearchIngestPipelineRead read existing ingest pipeline in Elasticsearch
+func resourceElasticsearchIngestPipelineRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Ingest.GetPipeline(
+		client.API.Ingest.GetPipeline.WithPipelineID(id),
+		client.API.Ingest.GetPipeline.WithContext(ctx),
+		client.API.Ingest.GetPipeline.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Ingest pipeline not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when get ingest pipeline %s: %s", id, res.String())
+
+	}
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Get ingest pipeline successfully", "body", string(b))
+	pipelines := make(IngestPipeline)
+	err = json.Unmarshal(b, &pipelines)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	pipeline, ok := pipelines[id]
+	if !ok {
+		tflog.Warn(ctx, "Ingest pipeline not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", id)
+	d.Set("description", pipeline.Description)
+	d.Set("version", pipeline.Version)
+
+	flattenProcessors, err := convertInterfaceToJsonString(pipeline.Processors)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("processors", flattenProcessors)
+
+	flattenOnFailure, err := convertInterfaceToJsonString(pipeline.OnFailure)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("on_failure", flattenOnFailure)
+
+	flattenMetadata, err := convertInterfaceToJsonString(pipeline.Metadata)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("metadata", flattenMetadata)
+
+	tflog.Info(ctx, "Read ingest pipeline successfully")
+
+	return nil
+}
+
+// resourceElasticsearchIngestPipelineUpdate update existing ingest pipeline in Elasticsearch
+func resourceElasticsearchIngestPipelineUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createIngestPipeline(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "Updated ingest pipeline successfully")
+
+	return resourceElasticsearchIngestPipelineRead(ctx, d, meta)
+}
+
+// resourceElasticsearchIngestPipelineDelete delete existing ingest pipeline in Elasticsearch
+func resourceElasticsearchIngestPipelineDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Ingest.DeletePipeline(
+		id,
+		client.API.Ingest.DeletePipeline.WithContext(ctx),
+		client.API.Ingest.DeletePipeline.WithPretty(),
+	)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Ingest pipeline not found - removing from state")
+			d.SetId("")
+			return nil
+
+		}
+		return diag.Errorf("Error when delete ingest pipeline %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted ingest pipeline successfully")
+	return nil
+
+}
+
+// Print IngestPipeline object as Json string
+func (r *IngestPipelineSpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// createIngestPipeline create or update ingest pipeline in Elasticsearch
+func createIngestPipeline(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("name").(string)
+
+	pipeline := &IngestPipelineSpec{
+		Description: d.Get("description").(string),
+		Processors:  optionalInterfaceJSON(d.Get("processors").(string)),
+		OnFailure:   optionalInterfaceJSON(d.Get("on_failure").(string)),
+		Version:     d.Get("version").(int),
+		Metadata:    optionalInterfaceJSON(d.Get("metadata").(string)),
+	}
+
+	tflog.Debug(ctx, "Ingest pipeline", "pipeline", pipeline.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Ingest.PutPipeline(
+		name,
+		strings.NewReader(pipeline.String()),
+		client.API.Ingest.PutPipeline.WithContext(ctx),
+		client.API.Ingest.PutPipeline.WithPretty(),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return errors.Errorf("Error when add ingest pipeline %s: %s", name, res.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchIngestPipeline(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchIngestPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchIngestPipeline,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchIngestPipelineExists("elasticsearch_ingest_pipeline.test"),
+				),
+			},
+			{
+				Config: testElasticsearchIngestPipelineUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchIngestPipelineExists("elasticsearch_ingest_pipeline.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ingest_pipeline.test", "version", "2"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_ingest_pipeline.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchIngestPipelineExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ingest pipeline ID is set")
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Ingest.GetPipeline(
+			client.API.Ingest.GetPipeline.WithPipelineID(rs.Primary.ID),
+			client.API.Ingest.GetPipeline.WithContext(context.Background()),
+			client.API.Ingest.GetPipeline.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when get ingest pipeline %s: %s", rs.Primary.ID, res.String())
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchIngestPipelineDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_ingest_pipeline" {
+			continue
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Ingest.GetPipeline(
+			client.API.Ingest.GetPipeline.WithPipelineID(rs.Primary.ID),
+			client.API.Ingest.GetPipeline.WithContext(context.Background()),
+			client.API.Ingest.GetPipeline.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			if res.StatusCode == 404 {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Ingest pipeline %q still exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+var testElasticsearchIngestPipeline = `
+resource "elasticsearch_ingest_pipeline" "test" {
+  name        = "terraform-test"
+  description = "Terraform test"
+  version     = 1
+  processors  = <<EOF
+[
+	{
+		"set": {
+			"field": "foo",
+			"value": "bar"
+		}
+	}
+]
+EOF
+}
+`
+
+var testElasticsearchIngestPipelineUpdate = `
+resource "elasticsearch_ingest_pipeline" "test" {
+  name        = "terraform-test"
+  description = "Terraform test"
+  version     = 2
+  processors  = <<EOF
+[
+	{
+		"set": {
+			"field": "foo",
+			"value": "bar2"
+		}
+	}
+]
+EOF
+  on_failure  = <<EOF
+[
+	{
+		"set": {
+			"field": "error.message",
+			"value": "{{ _ingest.on_failure_message }}"
+		}
+	}
+]
+EOF
+  metadata    = <<EOF
+{
+	"owner": "terraform"
+}
+EOF
+}
+`
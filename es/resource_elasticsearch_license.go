@@ -3,21 +3,21 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"strings"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // License object
@@ -39,13 +39,14 @@ type LicenseSpec struct {
 // resourceElasticsearchLicense handle the license API call
 func resourceElasticsearchLicense() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchLicenseCreate,
-		Read:   resourceElasticsearchLicenseRead,
-		Update: resourceElasticsearchLicenseUpdate,
-		Delete: resourceElasticsearchLicenseDelete,
+		CreateContext: resourceElasticsearchLicenseCreate,
+		ReadContext:   resourceElasticsearchLicenseRead,
+		UpdateContext: resourceElasticsearchLicenseUpdate,
+		DeleteContext: resourceElasticsearchLicenseDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -68,62 +69,66 @@ func resourceElasticsearchLicense() *schema.Resource {
 }
 
 // resourceElasticsearchLicenseCreate create license or enable basic license
-func resourceElasticsearchLicenseCreate(d *schema.ResourceData, meta interface{}) error {
-	err := createLicense(d, meta)
+func resourceElasticsearchLicenseCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, "license")
+
+	err := createLicense(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId("license")
-	return resourceElasticsearchLicenseRead(d, meta)
+	return resourceElasticsearchLicenseRead(ctx, d, meta)
 }
 
 // resourceElasticsearchLicense update license
-func resourceElasticsearchLicenseUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createLicense(d, meta)
+func resourceElasticsearchLicenseUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, "license")
+
+	err := createLicense(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceElasticsearchLicenseRead(d, meta)
+	return resourceElasticsearchLicenseRead(ctx, d, meta)
 }
 
 // resourceElasticsearchLicenseRead read license
-func resourceElasticsearchLicenseRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchLicenseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, "license")
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.License.Get(
-		client.API.License.Get.WithContext(context.Background()),
+		client.API.License.Get.WithContext(ctx),
 		client.API.License.Get.WithPretty(),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] License not found - removing from state")
-			log.Warnf("License not found - removing from state")
+			tflog.Warn(ctx, "License not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get license: %s", res.String())
+		return diag.Errorf("Error when get license: %s", res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get license successfully:\n%s", string(b))
+	tflog.Debug(ctx, "Get license successfully", "body", string(b))
 
 	license := make(License)
 	err = json.Unmarshal(b, &license)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	licenseSpec := license["license"]
 
-	log.Debugf("License object: %s", licenseSpec.String())
+	tflog.Debug(ctx, "License object", "license", licenseSpec.String())
 
 	if licenseSpec.Type == "basic" {
 		d.Set("basic_license", licenseSpec.String())
@@ -137,28 +142,28 @@ func resourceElasticsearchLicenseRead(d *schema.ResourceData, meta interface{})
 }
 
 // resourceElasticsearchLicenseDelete delete license
-func resourceElasticsearchLicenseDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchLicenseDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, "license")
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.License.Delete(
-		client.API.License.Delete.WithContext(context.Background()),
+		client.API.License.Delete.WithContext(ctx),
 		client.API.License.Delete.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] License not found - removing from state")
-			log.Warnf("License not found - removing from state")
+			tflog.Warn(ctx, "License not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when delete license: %s", res.String())
+		return diag.Errorf("Error when delete license: %s", res.String())
 
 	}
 
@@ -167,27 +172,27 @@ func resourceElasticsearchLicenseDelete(d *schema.ResourceData, meta interface{}
 }
 
 // createLicense add or update license
-func createLicense(d *schema.ResourceData, meta interface{}) error {
+func createLicense(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	license := d.Get("license").(string)
 	useBasicLicense := d.Get("use_basic_license").(bool)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	var err error
 	var res *esapi.Response
 	// Use enterprise lisence
 	if useBasicLicense == false {
-		log.Debug("Use enterprise license")
+		tflog.Debug(ctx, "Use enterprise license")
 		res, err = client.API.License.Post(
-			client.API.License.Post.WithContext(context.Background()),
+			client.API.License.Post.WithContext(ctx),
 			client.API.License.Post.WithPretty(),
 			client.API.License.Post.WithAcknowledge(true),
 			client.API.License.Post.WithBody(strings.NewReader(license)),
 		)
 	} else {
 		// Use basic lisence if needed (basic license not yet enabled)
-		log.Debug("Use basic license")
+		tflog.Debug(ctx, "Use basic license")
 		res, err = client.API.License.GetBasicStatus(
-			client.API.License.GetBasicStatus.WithContext(context.Background()),
+			client.API.License.GetBasicStatus.WithContext(ctx),
 			client.API.License.GetBasicStatus.WithPretty(),
 		)
 		if err != nil {
@@ -202,7 +207,7 @@ func createLicense(d *schema.ResourceData, meta interface{}) error {
 			return err
 		}
 
-		log.Debugf("Result when get basic license status: %s", string(b))
+		tflog.Debug(ctx, "Result when get basic license status", "body", string(b))
 
 		data := make(map[string]interface{})
 		err = json.Unmarshal(b, &data)
@@ -211,11 +216,11 @@ func createLicense(d *schema.ResourceData, meta interface{}) error {
 		}
 
 		if data["eligible_to_start_basic"].(bool) == false {
-			log.Infof("Basic license is already enabled")
+			tflog.Info(ctx, "Basic license is already enabled")
 			return nil
 		}
 		res, err = client.API.License.PostStartBasic(
-			client.API.License.PostStartBasic.WithContext(context.Background()),
+			client.API.License.PostStartBasic.WithContext(ctx),
 			client.API.License.PostStartBasic.WithPretty(),
 			client.API.License.PostStartBasic.WithAcknowledge(true),
 		)
@@ -0,0 +1,499 @@
+// Manage the machine learning datafeed in elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/ml-ad-apis.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// MLDatafeeds is the machine learning datafeeds object returned by API
+type MLDatafeeds struct {
+	Count     int               `json:"count"`
+	Datafeeds []*MLDatafeedSpec `json:"datafeeds"`
+}
+
+// MLDatafeedSpec is the machine learning datafeed specification
+type MLDatafeedSpec struct {
+	DatafeedID             string      `json:"datafeed_id,omitempty"`
+	JobID                  string      `json:"job_id,omitempty"`
+	Indices                []string    `json:"indices,omitempty"`
+	Query                  interface{} `json:"query,omitempty"`
+	Aggregations           interface{} `json:"aggregations,omitempty"`
+	ScriptFields           interface{} `json:"script_fields,omitempty"`
+	ChunkingConfig         interface{} `json:"chunking_config,omitempty"`
+	DelayedDataCheckConfig interface{} `json:"delayed_data_check_config,omitempty"`
+	Frequency              string      `json:"frequency,omitempty"`
+	QueryDelay             string      `json:"query_delay,omitempty"`
+	ScrollSize             int         `json:"scroll_size,omitempty"`
+}
+
+// MLDatafeedsStats is the machine learning datafeeds stats object returned by API
+type MLDatafeedsStats struct {
+	Count     int                `json:"count"`
+	Datafeeds []*MLDatafeedStats `json:"datafeeds"`
+}
+
+// MLDatafeedStats is the machine learning datafeed stats, used to know the datafeed state
+type MLDatafeedStats struct {
+	DatafeedID string `json:"datafeed_id"`
+	State      string `json:"state"`
+}
+
+// resourceElasticsearchMLDatafeed handle the machine learning datafeed API call
+func resourceElasticsearchMLDatafeed() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchMLDatafeedCreate,
+		ReadContext:   resourceElasticsearchMLDatafeedRead,
+		UpdateContext: resourceElasticsearchMLDatafeedUpdate,
+		DeleteContext: resourceElasticsearchMLDatafeedDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceElasticsearchMLDatafeedImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"job_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"indices": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"query": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"aggregations": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"script_fields": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"chunking_config": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"delayed_data_check_config": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"frequency": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"query_delay": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"scroll_size": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"started": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Start or stop the datafeed. The job must be opened to start the datafeed",
+			},
+		},
+	}
+}
+
+// resourceElasticsearchMLDatafeedCreate create new machine learning datafeed in Elasticsearch and start it if needed
+func resourceElasticsearchMLDatafeedCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	if err := meta.(*ProviderMeta).checkLicense("platinum", "Machine learning"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	datafeed := buildMLDatafeed(d)
+	datafeed.JobID = d.Get("job_id").(string)
+	tflog.Debug(ctx, "Machine learning datafeed", "datafeed", datafeed.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.PutDatafeed(
+		bytes.NewReader([]byte(datafeed.String())),
+		name,
+		client.API.ML.PutDatafeed.WithContext(ctx),
+		client.API.ML.PutDatafeed.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when add machine learning datafeed %s: %s", name, res.String())
+	}
+
+	d.SetId(name)
+
+	tflog.Info(ctx, "Created machine learning datafeed successfully")
+
+	if d.Get("started").(bool) {
+		if err := startMLDatafeed(ctx, name, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceElasticsearchMLDatafeedRead(ctx, d, meta)
+}
+
+// resourceElasticsearchMLDatafeedRead read existing machine learning datafeed and its state in Elasticsearch
+// The JSON fields only keep the keys set on state, to not see the default values as diff
+func resourceElasticsearchMLDatafeedRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	datafeed, err := getMLDatafeed(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if datafeed == nil {
+		tflog.Warn(ctx, "Machine learning datafeed not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	state, err := getMLDatafeedState(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Get machine learning datafeed successfully", "datafeed", datafeed.String(), "state", state)
+
+	d.Set("name", id)
+	d.Set("job_id", datafeed.JobID)
+	d.Set("indices", datafeed.Indices)
+	d.Set("frequency", datafeed.Frequency)
+	d.Set("query_delay", datafeed.QueryDelay)
+	d.Set("scroll_size", datafeed.ScrollSize)
+	d.Set("started", isMLDatafeedStarted(state))
+
+	jsonFields := map[string]interface{}{
+		"query":                     datafeed.Query,
+		"aggregations":              datafeed.Aggregations,
+		"script_fields":             datafeed.ScriptFields,
+		"chunking_config":           datafeed.ChunkingConfig,
+		"delayed_data_check_config": datafeed.DelayedDataCheckConfig,
+	}
+	for key, value := range jsonFields {
+		flattenValue, err := filterJSONFromState(d.Get(key).(string), value)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set(key, flattenValue)
+	}
+
+	return nil
+}
+
+// resourceElasticsearchMLDatafeedImport import existing machine learning datafeed with all its JSON fields
+func resourceElasticsearchMLDatafeedImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ctx = withLogID(ctx, d.Id())
+
+	datafeed, err := getMLDatafeed(ctx, d.Id(), meta)
+	if err != nil {
+		return nil, err
+	}
+	if datafeed == nil {
+		return nil, errors.Errorf("Machine learning datafeed %s not found", d.Id())
+	}
+
+	jsonFields := map[string]interface{}{
+		"query":                     datafeed.Query,
+		"aggregations":              datafeed.Aggregations,
+		"script_fields":             datafeed.ScriptFields,
+		"chunking_config":           datafeed.ChunkingConfig,
+		"delayed_data_check_config": datafeed.DelayedDataCheckConfig,
+	}
+	for key, value := range jsonFields {
+		flattenValue, err := convertInterfaceToJsonString(value)
+		if err != nil {
+			return nil, err
+		}
+		d.Set(key, flattenValue)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceElasticsearchMLDatafeedUpdate update existing machine learning datafeed and start or stop it
+// The datafeed is stopped during the update, and started again if needed
+func resourceElasticsearchMLDatafeedUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	if d.HasChangesExcept("started") {
+		state, err := getMLDatafeedState(ctx, id, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if isMLDatafeedStarted(state) {
+			if err := stopMLDatafeed(ctx, id, meta); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		datafeed := buildMLDatafeed(d)
+		tflog.Debug(ctx, "Machine learning datafeed", "datafeed", datafeed.String())
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.ML.UpdateDatafeed(
+			bytes.NewReader([]byte(datafeed.String())),
+			id,
+			client.API.ML.UpdateDatafeed.WithContext(ctx),
+			client.API.ML.UpdateDatafeed.WithPretty(),
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return diag.Errorf("Error when update machine learning datafeed %s: %s", id, res.String())
+		}
+
+		tflog.Info(ctx, "Updated machine learning datafeed successfully")
+
+		if d.Get("started").(bool) {
+			if err := startMLDatafeed(ctx, id, meta); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	} else if d.HasChange("started") {
+		if d.Get("started").(bool) {
+			err := startMLDatafeed(ctx, id, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		} else {
+			err := stopMLDatafeed(ctx, id, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return resourceElasticsearchMLDatafeedRead(ctx, d, meta)
+}
+
+// resourceElasticsearchMLDatafeedDelete stop and delete existing machine learning datafeed in Elasticsearch
+func resourceElasticsearchMLDatafeedDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	state, err := getMLDatafeedState(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if state == "" {
+		tflog.Warn(ctx, "Machine learning datafeed not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+	if state != "stopped" {
+		if err := stopMLDatafeed(ctx, id, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.DeleteDatafeed(
+		id,
+		client.API.ML.DeleteDatafeed.WithContext(ctx),
+		client.API.ML.DeleteDatafeed.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Machine learning datafeed not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete machine learning datafeed %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted machine learning datafeed successfully")
+	return nil
+}
+
+// Print MLDatafeedSpec object as Json string
+func (r *MLDatafeedSpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// buildMLDatafeed return the machine learning datafeed without the job ID, that can't be updated
+func buildMLDatafeed(d *schema.ResourceData) *MLDatafeedSpec {
+	datafeed := &MLDatafeedSpec{
+		Indices:                convertArrayInterfaceToArrayString(d.Get("indices").([]interface{})),
+		Query:                  optionalInterfaceJSON(d.Get("query").(string)),
+		Aggregations:           optionalInterfaceJSON(d.Get("aggregations").(string)),
+		ScriptFields:           optionalInterfaceJSON(d.Get("script_fields").(string)),
+		ChunkingConfig:         optionalInterfaceJSON(d.Get("chunking_config").(string)),
+		DelayedDataCheckConfig: optionalInterfaceJSON(d.Get("delayed_data_check_config").(string)),
+		Frequency:              d.Get("frequency").(string),
+		QueryDelay:             d.Get("query_delay").(string),
+		ScrollSize:             d.Get("scroll_size").(int),
+	}
+
+	// Elasticsearch keep the previous query when it's not sent, so set the default query when it's removed
+	if datafeed.Query == nil && d.HasChange("query") {
+		datafeed.Query = map[string]interface{}{
+			"match_all": map[string]interface{}{},
+		}
+	}
+
+	return datafeed
+}
+
+// getMLDatafeed return the machine learning datafeed from Elasticsearch, or nil if not found
+func getMLDatafeed(ctx context.Context, id string, meta interface{}) (*MLDatafeedSpec, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.GetDatafeeds(
+		client.API.ML.GetDatafeeds.WithDatafeedID(id),
+		client.API.ML.GetDatafeeds.WithContext(ctx),
+		client.API.ML.GetDatafeeds.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get machine learning datafeed %s: %s", id, res.String())
+	}
+
+	datafeeds := &MLDatafeeds{}
+	if err := json.NewDecoder(res.Body).Decode(datafeeds); err != nil {
+		return nil, err
+	}
+	if len(datafeeds.Datafeeds) == 0 {
+		return nil, nil
+	}
+
+	return datafeeds.Datafeeds[0], nil
+}
+
+// getMLDatafeedState return the current state of machine learning datafeed, like started or stopped. It return empty string if not found
+func getMLDatafeedState(ctx context.Context, id string, meta interface{}) (string, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.GetDatafeedStats(
+		client.API.ML.GetDatafeedStats.WithDatafeedID(id),
+		client.API.ML.GetDatafeedStats.WithContext(ctx),
+		client.API.ML.GetDatafeedStats.WithPretty(),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return "", nil
+		}
+		return "", errors.Errorf("Error when get machine learning datafeed stats %s: %s", id, res.String())
+	}
+
+	stats := &MLDatafeedsStats{}
+	if err := json.NewDecoder(res.Body).Decode(stats); err != nil {
+		return "", err
+	}
+	if len(stats.Datafeeds) == 0 {
+		return "", nil
+	}
+
+	return stats.Datafeeds[0].State, nil
+}
+
+// isMLDatafeedStarted return true if the machine learning datafeed state is a started state
+func isMLDatafeedStarted(state string) bool {
+	switch state {
+	case "started", "starting":
+		return true
+	default:
+		return false
+	}
+}
+
+// startMLDatafeed start the machine learning datafeed
+func startMLDatafeed(ctx context.Context, id string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.StartDatafeed(
+		id,
+		client.API.ML.StartDatafeed.WithContext(ctx),
+		client.API.ML.StartDatafeed.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when start machine learning datafeed %s: %s", id, res.String())
+	}
+
+	tflog.Info(ctx, "Started machine learning datafeed successfully")
+
+	return nil
+}
+
+// stopMLDatafeed stop the machine learning datafeed
+func stopMLDatafeed(ctx context.Context, id string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.StopDatafeed(
+		id,
+		client.API.ML.StopDatafeed.WithContext(ctx),
+		client.API.ML.StopDatafeed.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when stop machine learning datafeed %s: %s", id, res.String())
+	}
+
+	tflog.Info(ctx, "Stopped machine learning datafeed successfully")
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchMLDatafeed(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchMLDatafeedDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchMLDatafeed,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchMLDatafeedExists("elasticsearch_ml_datafeed.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ml_datafeed.test", "started", "false"),
+				),
+			},
+			{
+				Config: testElasticsearchMLDatafeedUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchMLDatafeedExists("elasticsearch_ml_datafeed.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ml_datafeed.test", "query_delay", "120s"),
+					resource.TestCheckResourceAttr("elasticsearch_ml_datafeed.test", "started", "true"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_ml_datafeed.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"query", "chunking_config", "delayed_data_check_config"},
+			},
+		},
+	})
+}
+
+func TestBuildMLDatafeedRemoveQuery(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "terraform-test",
+		Attributes: map[string]string{
+			"indices.#": "1",
+			"indices.0": "terraform-test",
+			"query":     `{"term": {"status": "error"}}`,
+		},
+	}
+	diff := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"query": {
+				Old: `{"term": {"status": "error"}}`,
+				New: "",
+			},
+		},
+	}
+	d, err := schema.InternalMap(resourceElasticsearchMLDatafeed().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"match_all": map[string]interface{}{},
+	}
+	if datafeed := buildMLDatafeed(d); !reflect.DeepEqual(datafeed.Query, expected) {
+		t.Errorf("Removed query must be reset to %+v, got %+v", expected, datafeed.Query)
+	}
+}
+
+func testCheckElasticsearchMLDatafeedExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No machine learning datafeed ID is set")
+		}
+
+		datafeed, err := getMLDatafeed(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if datafeed == nil {
+			return errors.Errorf("Machine learning datafeed %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchMLDatafeedDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_ml_datafeed" {
+			continue
+		}
+
+		datafeed, err := getMLDatafeed(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if datafeed != nil {
+			return fmt.Errorf("Machine learning datafeed %q still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testElasticsearchMLDatafeed = `
+resource "elasticsearch_index" "test_datafeed" {
+  name     = "terraform-test-datafeed"
+  mappings = <<EOF
+{
+  "properties": {
+    "@timestamp": { "type": "date" }
+  }
+}
+EOF
+  deletion_protection = false
+}
+
+resource "elasticsearch_ml_job" "test_datafeed" {
+  name             = "terraform-test-datafeed"
+  analysis_config  = <<EOF
+{
+  "bucket_span": "15m",
+  "detectors": [
+    {
+      "function": "count"
+    }
+  ]
+}
+EOF
+  data_description = <<EOF
+{
+  "time_field": "@timestamp"
+}
+EOF
+  open             = true
+}
+
+resource "elasticsearch_ml_datafeed" "test" {
+  name    = "datafeed-terraform-test"
+  job_id  = elasticsearch_ml_job.test_datafeed.name
+  indices = [elasticsearch_index.test_datafeed.name]
+  query   = <<EOF
+{
+  "match_all": {}
+}
+EOF
+}
+`
+
+var testElasticsearchMLDatafeedUpdate = `
+resource "elasticsearch_index" "test_datafeed" {
+  name     = "terraform-test-datafeed"
+  mappings = <<EOF
+{
+  "properties": {
+    "@timestamp": { "type": "date" }
+  }
+}
+EOF
+  deletion_protection = false
+}
+
+resource "elasticsearch_ml_job" "test_datafeed" {
+  name             = "terraform-test-datafeed"
+  analysis_config  = <<EOF
+{
+  "bucket_span": "15m",
+  "detectors": [
+    {
+      "function": "count"
+    }
+  ]
+}
+EOF
+  data_description = <<EOF
+{
+  "time_field": "@timestamp"
+}
+EOF
+  open             = true
+}
+
+resource "elasticsearch_ml_datafeed" "test" {
+  name        = "datafeed-terraform-test"
+  job_id      = elasticsearch_ml_job.test_datafeed.name
+  indices     = [elasticsearch_index.test_datafeed.name]
+  query       = <<EOF
+{
+  "match_all": {}
+}
+EOF
+  query_delay = "120s"
+  started     = true
+}
+`
@@ -0,0 +1,538 @@
+// Manage the machine learning anomaly detection job in elasticsearch
+// The analysis can't be updated, so change it create new job
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/ml-ad-apis.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// MLJobs is the machine learning jobs object returned by API
+type MLJobs struct {
+	Count int          `json:"count"`
+	Jobs  []*MLJobSpec `json:"jobs"`
+}
+
+// MLJobSpec is the machine learning job specification
+type MLJobSpec struct {
+	JobID                      string      `json:"job_id,omitempty"`
+	Description                string      `json:"description"`
+	Groups                     []string    `json:"groups"`
+	AnalysisConfig             interface{} `json:"analysis_config,omitempty"`
+	AnalysisLimits             interface{} `json:"analysis_limits,omitempty"`
+	DataDescription            interface{} `json:"data_description,omitempty"`
+	ModelPlotConfig            interface{} `json:"model_plot_config,omitempty"`
+	ResultsIndexName           string      `json:"results_index_name,omitempty"`
+	ModelSnapshotRetentionDays *int        `json:"model_snapshot_retention_days,omitempty"`
+	ResultsRetentionDays       *int        `json:"results_retention_days,omitempty"`
+	BackgroundPersistInterval  string      `json:"background_persist_interval,omitempty"`
+	CustomSettings             interface{} `json:"custom_settings,omitempty"`
+	AllowLazyOpen              bool        `json:"allow_lazy_open"`
+}
+
+// MLJobsStats is the machine learning jobs stats object returned by API
+type MLJobsStats struct {
+	Count int           `json:"count"`
+	Jobs  []*MLJobStats `json:"jobs"`
+}
+
+// MLJobStats is the machine learning job stats, used to know the job state
+type MLJobStats struct {
+	JobID string `json:"job_id"`
+	State string `json:"state"`
+}
+
+// resourceElasticsearchMLJob handle the machine learning job API call
+func resourceElasticsearchMLJob() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchMLJobCreate,
+		ReadContext:   resourceElasticsearchMLJobRead,
+		UpdateContext: resourceElasticsearchMLJobUpdate,
+		DeleteContext: resourceElasticsearchMLJobDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceElasticsearchMLJobImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"groups": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"analysis_config": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"analysis_limits": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"data_description": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"model_plot_config": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"results_index_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"model_snapshot_retention_days": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"results_retention_days": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"background_persist_interval": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"custom_settings": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"allow_lazy_open": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"open": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Open or close the job",
+			},
+		},
+	}
+}
+
+// resourceElasticsearchMLJobCreate create new machine learning job in Elasticsearch and open it if needed
+func resourceElasticsearchMLJobCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	if err := meta.(*ProviderMeta).checkLicense("platinum", "Machine learning"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	job := buildMLJob(d)
+	job.AnalysisConfig = optionalInterfaceJSON(d.Get("analysis_config").(string))
+	job.AnalysisLimits = optionalInterfaceJSON(d.Get("analysis_limits").(string))
+	job.DataDescription = optionalInterfaceJSON(d.Get("data_description").(string))
+	job.ResultsIndexName = d.Get("results_index_name").(string)
+	tflog.Debug(ctx, "Machine learning job", "job", job.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.PutJob(
+		name,
+		bytes.NewReader([]byte(job.String())),
+		client.API.ML.PutJob.WithContext(ctx),
+		client.API.ML.PutJob.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when add machine learning job %s: %s", name, res.String())
+	}
+
+	d.SetId(name)
+
+	tflog.Info(ctx, "Created machine learning job successfully")
+
+	if d.Get("open").(bool) {
+		if err := openMLJob(ctx, name, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceElasticsearchMLJobRead(ctx, d, meta)
+}
+
+// resourceElasticsearchMLJobRead read existing machine learning job and its state in Elasticsearch
+// The JSON fields only keep the keys set on state, to not see the default values as diff
+func resourceElasticsearchMLJobRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	job, err := getMLJob(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if job == nil {
+		tflog.Warn(ctx, "Machine learning job not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	state, err := getMLJobState(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Get machine learning job successfully", "job", job.String(), "state", state)
+
+	d.Set("name", id)
+	d.Set("description", job.Description)
+	d.Set("groups", job.Groups)
+	d.Set("results_index_name", job.ResultsIndexName)
+	if job.ModelSnapshotRetentionDays != nil {
+		d.Set("model_snapshot_retention_days", *job.ModelSnapshotRetentionDays)
+	}
+	if job.ResultsRetentionDays != nil {
+		d.Set("results_retention_days", *job.ResultsRetentionDays)
+	} else {
+		d.Set("results_retention_days", nil)
+	}
+	d.Set("background_persist_interval", job.BackgroundPersistInterval)
+	d.Set("allow_lazy_open", job.AllowLazyOpen)
+	d.Set("open", isMLJobOpened(state))
+
+	jsonFields := map[string]interface{}{
+		"analysis_config":   job.AnalysisConfig,
+		"analysis_limits":   job.AnalysisLimits,
+		"data_description":  job.DataDescription,
+		"model_plot_config": job.ModelPlotConfig,
+		"custom_settings":   job.CustomSettings,
+	}
+	for key, value := range jsonFields {
+		flattenValue, err := filterJSONFromState(d.Get(key).(string), value)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set(key, flattenValue)
+	}
+
+	return nil
+}
+
+// resourceElasticsearchMLJobImport import existing machine learning job with all its JSON fields
+func resourceElasticsearchMLJobImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ctx = withLogID(ctx, d.Id())
+
+	job, err := getMLJob(ctx, d.Id(), meta)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, errors.Errorf("Machine learning job %s not found", d.Id())
+	}
+
+	jsonFields := map[string]interface{}{
+		"analysis_config":   job.AnalysisConfig,
+		"analysis_limits":   job.AnalysisLimits,
+		"data_description":  job.DataDescription,
+		"model_plot_config": job.ModelPlotConfig,
+		"custom_settings":   job.CustomSettings,
+	}
+	for key, value := range jsonFields {
+		flattenValue, err := convertInterfaceToJsonString(value)
+		if err != nil {
+			return nil, err
+		}
+		d.Set(key, flattenValue)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceElasticsearchMLJobUpdate update the updatable fields of existing machine learning job and open or close it
+func resourceElasticsearchMLJobUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	if d.HasChangesExcept("open") {
+		job, err := buildMLJobUpdate(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		b, err := json.Marshal(job)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		tflog.Debug(ctx, "Machine learning job", "job", string(b))
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.ML.UpdateJob(
+			id,
+			bytes.NewReader(b),
+			client.API.ML.UpdateJob.WithContext(ctx),
+			client.API.ML.UpdateJob.WithPretty(),
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return diag.Errorf("Error when update machine learning job %s: %s", id, res.String())
+		}
+
+		tflog.Info(ctx, "Updated machine learning job successfully")
+	}
+
+	if d.HasChange("open") {
+		if d.Get("open").(bool) {
+			err := openMLJob(ctx, id, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		} else {
+			err := closeMLJob(ctx, id, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return resourceElasticsearchMLJobRead(ctx, d, meta)
+}
+
+// resourceElasticsearchMLJobDelete close and delete existing machine learning job in Elasticsearch
+func resourceElasticsearchMLJobDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	state, err := getMLJobState(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if state == "" {
+		tflog.Warn(ctx, "Machine learning job not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+	if state != "closed" {
+		if err := closeMLJob(ctx, id, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.DeleteJob(
+		id,
+		client.API.ML.DeleteJob.WithWaitForCompletion(true),
+		client.API.ML.DeleteJob.WithContext(ctx),
+		client.API.ML.DeleteJob.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Machine learning job not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete machine learning job %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted machine learning job successfully")
+	return nil
+}
+
+// Print MLJobSpec object as Json string
+func (r *MLJobSpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// buildMLJob return the machine learning job with only the updatable fields
+func buildMLJob(d *schema.ResourceData) *MLJobSpec {
+	job := &MLJobSpec{
+		Description:               d.Get("description").(string),
+		Groups:                    convertArrayInterfaceToArrayString(d.Get("groups").([]interface{})),
+		ModelPlotConfig:           optionalInterfaceJSON(d.Get("model_plot_config").(string)),
+		BackgroundPersistInterval: d.Get("background_persist_interval").(string),
+		CustomSettings:            optionalInterfaceJSON(d.Get("custom_settings").(string)),
+		AllowLazyOpen:             d.Get("allow_lazy_open").(bool),
+	}
+
+	if v, ok := d.GetOk("model_snapshot_retention_days"); ok {
+		modelSnapshotRetentionDays := v.(int)
+		job.ModelSnapshotRetentionDays = &modelSnapshotRetentionDays
+	}
+	if v, ok := d.GetOk("results_retention_days"); ok {
+		resultsRetentionDays := v.(int)
+		job.ResultsRetentionDays = &resultsRetentionDays
+	}
+
+	return job
+}
+
+// buildMLJobUpdate return the machine learning job to update, with the removed fields explicitly reset
+func buildMLJobUpdate(d *schema.ResourceData) (map[string]interface{}, error) {
+	job := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(buildMLJob(d).String()), &job); err != nil {
+		return nil, err
+	}
+
+	resetValues := map[string]interface{}{
+		"model_plot_config":             map[string]interface{}{"enabled": false},
+		"model_snapshot_retention_days": nil,
+		"results_retention_days":        nil,
+		"background_persist_interval":   nil,
+		"custom_settings":               map[string]interface{}{},
+	}
+	for key, value := range resetValues {
+		if _, ok := d.GetOk(key); !ok && d.HasChange(key) {
+			job[key] = value
+		}
+	}
+
+	return job, nil
+}
+
+// getMLJob return the machine learning job from Elasticsearch, or nil if not found
+func getMLJob(ctx context.Context, id string, meta interface{}) (*MLJobSpec, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.GetJobs(
+		client.API.ML.GetJobs.WithJobID(id),
+		client.API.ML.GetJobs.WithContext(ctx),
+		client.API.ML.GetJobs.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get machine learning job %s: %s", id, res.String())
+	}
+
+	jobs := &MLJobs{}
+	if err := json.NewDecoder(res.Body).Decode(jobs); err != nil {
+		return nil, err
+	}
+	if len(jobs.Jobs) == 0 {
+		return nil, nil
+	}
+
+	return jobs.Jobs[0], nil
+}
+
+// getMLJobState return the current state of machine learning job, like opened or closed. It return empty string if not found
+func getMLJobState(ctx context.Context, id string, meta interface{}) (string, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.GetJobStats(
+		client.API.ML.GetJobStats.WithJobID(id),
+		client.API.ML.GetJobStats.WithContext(ctx),
+		client.API.ML.GetJobStats.WithPretty(),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return "", nil
+		}
+		return "", errors.Errorf("Error when get machine learning job stats %s: %s", id, res.String())
+	}
+
+	stats := &MLJobsStats{}
+	if err := json.NewDecoder(res.Body).Decode(stats); err != nil {
+		return "", err
+	}
+	if len(stats.Jobs) == 0 {
+		return "", nil
+	}
+
+	return stats.Jobs[0].State, nil
+}
+
+// isMLJobOpened return true if the machine learning job state is an opened state
+func isMLJobOpened(state string) bool {
+	switch state {
+	case "opened", "opening":
+		return true
+	default:
+		return false
+	}
+}
+
+// openMLJob open the machine learning job
+func openMLJob(ctx context.Context, id string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.OpenJob(
+		id,
+		client.API.ML.OpenJob.WithContext(ctx),
+		client.API.ML.OpenJob.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when open machine learning job %s: %s", id, res.String())
+	}
+
+	tflog.Info(ctx, "Opened machine learning job successfully")
+
+	return nil
+}
+
+// closeMLJob close the machine learning job
+func closeMLJob(ctx context.Context, id string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.ML.CloseJob(
+		id,
+		client.API.ML.CloseJob.WithContext(ctx),
+		client.API.ML.CloseJob.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when close machine learning job %s: %s", id, res.String())
+	}
+
+	tflog.Info(ctx, "Closed machine learning job successfully")
+
+	return nil
+}
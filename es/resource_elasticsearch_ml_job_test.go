@@ -0,0 +1,183 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchMLJob(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchMLJobDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchMLJob,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchMLJobExists("elasticsearch_ml_job.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ml_job.test", "open", "false"),
+				),
+			},
+			{
+				Config: testElasticsearchMLJobUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchMLJobExists("elasticsearch_ml_job.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ml_job.test", "description", "Updated by Terraform"),
+					resource.TestCheckResourceAttr("elasticsearch_ml_job.test", "open", "true"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_ml_job.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"analysis_config", "analysis_limits", "data_description"},
+			},
+		},
+	})
+}
+
+func TestBuildMLJobUpdate(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "terraform-test",
+		Attributes: map[string]string{
+			"description":                 "test",
+			"results_retention_days":      "30",
+			"background_persist_interval": "2h",
+			"custom_settings":             `{"team": "ops"}`,
+		},
+	}
+	diff := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"description": {
+				Old: "test",
+				New: "",
+			},
+			"results_retention_days": {
+				Old: "30",
+				New: "",
+			},
+			"custom_settings": {
+				Old: `{"team": "ops"}`,
+				New: "",
+			},
+		},
+	}
+	d, err := schema.InternalMap(resourceElasticsearchMLJob().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	job, err := buildMLJobUpdate(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"description":                 "",
+		"groups":                      []interface{}{},
+		"background_persist_interval": "2h",
+		"results_retention_days":      nil,
+		"custom_settings":             map[string]interface{}{},
+		"allow_lazy_open":             false,
+	}
+	if !reflect.DeepEqual(job, expected) {
+		t.Errorf("Removed fields must be reset, expected %+v, got %+v", expected, job)
+	}
+}
+
+func testCheckElasticsearchMLJobExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No machine learning job ID is set")
+		}
+
+		job, err := getMLJob(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return errors.Errorf("Machine learning job %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchMLJobDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_ml_job" {
+			continue
+		}
+
+		job, err := getMLJob(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if job != nil {
+			return fmt.Errorf("Machine learning job %q still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testElasticsearchMLJob = `
+resource "elasticsearch_ml_job" "test" {
+  name             = "terraform-test"
+  groups           = ["terraform"]
+  analysis_config  = <<EOF
+{
+  "bucket_span": "15m",
+  "detectors": [
+    {
+      "function": "count"
+    }
+  ]
+}
+EOF
+  data_description = <<EOF
+{
+  "time_field": "@timestamp"
+}
+EOF
+}
+`
+
+var testElasticsearchMLJobUpdate = `
+resource "elasticsearch_ml_job" "test" {
+  name             = "terraform-test"
+  description      = "Updated by Terraform"
+  groups           = ["terraform"]
+  analysis_config  = <<EOF
+{
+  "bucket_span": "15m",
+  "detectors": [
+    {
+      "function": "count"
+    }
+  ]
+}
+EOF
+  data_description = <<EOF
+{
+  "time_field": "@timestamp"
+}
+EOF
+  results_retention_days = 30
+  open                   = true
+}
+`
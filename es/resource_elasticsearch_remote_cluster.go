@@ -0,0 +1,421 @@
+// Manage the remote cluster in Elasticsearch
+// The remote cluster is stored on persistent cluster settings `cluster.remote.<alias>`, only these keys are managed
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/remote-clusters-settings.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+// RemoteClusterSpec is the remote cluster specification stored on cluster settings
+type RemoteClusterSpec struct {
+	Mode                   string
+	Seeds                  []string
+	NodeConnections        int
+	ProxyAddress           string
+	ProxySocketConnections int
+	ServerName             string
+	SkipUnavailable        bool
+}
+
+// RemoteClustersInfo is the remote clusters info returned by API, the key is the remote cluster alias
+type RemoteClustersInfo map[string]*RemoteClusterInfo
+
+// RemoteClusterInfo is the remote cluster info
+type RemoteClusterInfo struct {
+	Connected                 bool     `json:"connected"`
+	Mode                      string   `json:"mode"`
+	Seeds                     []string `json:"seeds,omitempty"`
+	ProxyAddress              string   `json:"proxy_address,omitempty"`
+	NumNodesConnected         int      `json:"num_nodes_connected"`
+	NumProxySocketsConnected  int      `json:"num_proxy_sockets_connected"`
+	MaxConnectionsPerCluster  int      `json:"max_connections_per_cluster"`
+	MaxProxySocketConnections int      `json:"max_proxy_socket_connections"`
+	InitialConnectTimeout     string   `json:"initial_connect_timeout"`
+	SkipUnavailable           bool     `json:"skip_unavailable"`
+}
+
+// remoteClusterSettingKeys is the settings managed for each remote cluster, under `cluster.remote.<alias>`
+var remoteClusterSettingKeys = []string{
+	"mode",
+	"seeds",
+	"node_connections",
+	"proxy_address",
+	"proxy_socket_connections",
+	"server_name",
+	"skip_unavailable",
+}
+
+// resourceElasticsearchRemoteCluster handle the remote cluster settings
+func resourceElasticsearchRemoteCluster() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchRemoteClusterCreate,
+		ReadContext:   resourceElasticsearchRemoteClusterRead,
+		UpdateContext: resourceElasticsearchRemoteClusterUpdate,
+		DeleteContext: resourceElasticsearchRemoteClusterDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourceElasticsearchRemoteClusterCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The remote cluster alias",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "sniff",
+				ValidateFunc: validation.StringInSlice([]string{"sniff", "proxy"}, false),
+			},
+			"seeds": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"node_connections": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"proxy_address": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"proxy_socket_connections": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"server_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"skip_unavailable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"connected": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"num_nodes_connected": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"num_proxy_sockets_connected": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceElasticsearchRemoteClusterCreate add the remote cluster on persistent cluster settings
+func resourceElasticsearchRemoteClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	err := putRemoteClusterSettings(ctx, name, buildRemoteCluster(d), meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(name)
+
+	tflog.Info(ctx, "Created remote cluster successfully")
+
+	return resourceElasticsearchRemoteClusterRead(ctx, d, meta)
+}
+
+// resourceElasticsearchRemoteClusterRead read the remote cluster settings and its connection status
+func resourceElasticsearchRemoteClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Cluster.GetSettings(
+		client.API.Cluster.GetSettings.WithFlatSettings(true),
+		client.API.Cluster.GetSettings.WithContext(ctx),
+		client.API.Cluster.GetSettings.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when get cluster settings: %s", res.String())
+	}
+
+	clusterSettings := &ClusterSettings{}
+	if err := json.NewDecoder(res.Body).Decode(clusterSettings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	remoteCluster, err := parseRemoteClusterSettings(id, clusterSettings.Persistent)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if remoteCluster == nil {
+		tflog.Warn(ctx, "Remote cluster not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	remoteClusterInfo, err := getRemoteClusterInfo(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("name", id)
+	d.Set("mode", remoteCluster.Mode)
+	d.Set("seeds", remoteCluster.Seeds)
+	d.Set("node_connections", remoteCluster.NodeConnections)
+	d.Set("proxy_address", remoteCluster.ProxyAddress)
+	d.Set("proxy_socket_connections", remoteCluster.ProxySocketConnections)
+	d.Set("server_name", remoteCluster.ServerName)
+	d.Set("skip_unavailable", remoteCluster.SkipUnavailable)
+
+	if remoteClusterInfo != nil {
+		tflog.Debug(ctx, "Get remote cluster successfully", "connected", remoteClusterInfo.Connected)
+
+		d.Set("connected", remoteClusterInfo.Connected)
+		d.Set("num_nodes_connected", remoteClusterInfo.NumNodesConnected)
+		d.Set("num_proxy_sockets_connected", remoteClusterInfo.NumProxySocketsConnected)
+	} else {
+		d.Set("connected", false)
+		d.Set("num_nodes_connected", 0)
+		d.Set("num_proxy_sockets_connected", 0)
+	}
+
+	return nil
+}
+
+// resourceElasticsearchRemoteClusterUpdate update the remote cluster settings
+func resourceElasticsearchRemoteClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	err := putRemoteClusterSettings(ctx, id, buildRemoteCluster(d), meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "Updated remote cluster successfully")
+
+	return resourceElasticsearchRemoteClusterRead(ctx, d, meta)
+}
+
+// resourceElasticsearchRemoteClusterDelete remove the remote cluster settings
+func resourceElasticsearchRemoteClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	err := putRemoteClusterSettings(ctx, id, nil, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted remote cluster successfully")
+	return nil
+}
+
+// resourceElasticsearchRemoteClusterCustomizeDiff check that only the fields of the mode are set
+func resourceElasticsearchRemoteClusterCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("mode") {
+		return nil
+	}
+
+	mode := d.Get("mode").(string)
+	fields := map[string]bool{
+		"seeds":                    len(d.Get("seeds").([]interface{})) > 0,
+		"node_connections":         d.Get("node_connections").(int) > 0,
+		"proxy_address":            d.Get("proxy_address").(string) != "",
+		"proxy_socket_connections": d.Get("proxy_socket_connections").(int) > 0,
+		"server_name":              d.Get("server_name").(string) != "",
+	}
+	for _, field := range remoteClusterSettingKeys {
+		if isSet, ok := fields[field]; ok && isSet && remoteClusterSettingMode(field) != mode {
+			return errors.Errorf("%s can only be used with mode %s, got mode %s", field, remoteClusterSettingMode(field), mode)
+		}
+	}
+
+	return nil
+}
+
+// remoteClusterSettingMode return the mode where the remote cluster setting can be used
+func remoteClusterSettingMode(field string) string {
+	switch field {
+	case "proxy_address", "proxy_socket_connections", "server_name":
+		return "proxy"
+	default:
+		return "sniff"
+	}
+}
+
+// buildRemoteCluster return the remote cluster from resource data
+func buildRemoteCluster(d *schema.ResourceData) *RemoteClusterSpec {
+	return &RemoteClusterSpec{
+		Mode:                   d.Get("mode").(string),
+		Seeds:                  convertArrayInterfaceToArrayString(d.Get("seeds").([]interface{})),
+		NodeConnections:        d.Get("node_connections").(int),
+		ProxyAddress:           d.Get("proxy_address").(string),
+		ProxySocketConnections: d.Get("proxy_socket_connections").(int),
+		ServerName:             d.Get("server_name").(string),
+		SkipUnavailable:        d.Get("skip_unavailable").(bool),
+	}
+}
+
+// putRemoteClusterSettings set all the settings of remote cluster. The settings not used are reset, so nil remote cluster remove it
+func putRemoteClusterSettings(ctx context.Context, alias string, remoteCluster *RemoteClusterSpec, meta interface{}) error {
+	clusterSettings := &ClusterSettings{
+		Persistent: buildRemoteClusterSettings(alias, remoteCluster),
+	}
+
+	return putClusterSettings(ctx, clusterSettings, meta)
+}
+
+// buildRemoteClusterSettings return the flat cluster settings of remote cluster
+// All managed keys are returned, the unused keys are set to nil to reset them
+func buildRemoteClusterSettings(alias string, remoteCluster *RemoteClusterSpec) map[string]interface{} {
+	prefix := fmt.Sprintf("cluster.remote.%s.", alias)
+	settings := make(map[string]interface{})
+	for _, key := range remoteClusterSettingKeys {
+		settings[prefix+key] = nil
+	}
+	if remoteCluster == nil {
+		return settings
+	}
+
+	// Sniff is the default mode, and the mode setting not exist before Elasticsearch 7.7
+	if remoteCluster.Mode != "" && remoteCluster.Mode != "sniff" {
+		settings[prefix+"mode"] = remoteCluster.Mode
+	}
+	if len(remoteCluster.Seeds) > 0 {
+		settings[prefix+"seeds"] = remoteCluster.Seeds
+	}
+	if remoteCluster.NodeConnections > 0 {
+		settings[prefix+"node_connections"] = remoteCluster.NodeConnections
+	}
+	if remoteCluster.ProxyAddress != "" {
+		settings[prefix+"proxy_address"] = remoteCluster.ProxyAddress
+	}
+	if remoteCluster.ProxySocketConnections > 0 {
+		settings[prefix+"proxy_socket_connections"] = remoteCluster.ProxySocketConnections
+	}
+	if remoteCluster.ServerName != "" {
+		settings[prefix+"server_name"] = remoteCluster.ServerName
+	}
+	if remoteCluster.SkipUnavailable {
+		settings[prefix+"skip_unavailable"] = true
+	}
+
+	return settings
+}
+
+// parseRemoteClusterSettings return the remote cluster from flat cluster settings, or nil if there are no settings for it
+func parseRemoteClusterSettings(alias string, settings map[string]interface{}) (*RemoteClusterSpec, error) {
+	prefix := fmt.Sprintf("cluster.remote.%s.", alias)
+	remoteCluster := &RemoteClusterSpec{
+		Mode: "sniff",
+	}
+	found := false
+
+	for key, value := range settings {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		found = true
+
+		var err error
+		switch strings.TrimPrefix(key, prefix) {
+		case "mode":
+			remoteCluster.Mode = fmt.Sprintf("%v", value)
+		case "seeds":
+			if seeds, ok := value.([]interface{}); ok {
+				remoteCluster.Seeds = convertArrayInterfaceToArrayString(seeds)
+			} else {
+				remoteCluster.Seeds = strings.Split(fmt.Sprintf("%v", value), ",")
+			}
+		case "node_connections":
+			remoteCluster.NodeConnections, err = strconv.Atoi(fmt.Sprintf("%v", value))
+		case "proxy_address":
+			remoteCluster.ProxyAddress = fmt.Sprintf("%v", value)
+		case "proxy_socket_connections":
+			remoteCluster.ProxySocketConnections, err = strconv.Atoi(fmt.Sprintf("%v", value))
+		case "server_name":
+			remoteCluster.ServerName = fmt.Sprintf("%v", value)
+		case "skip_unavailable":
+			remoteCluster.SkipUnavailable, err = strconv.ParseBool(fmt.Sprintf("%v", value))
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error when parse setting %s", key)
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return remoteCluster, nil
+}
+
+// getRemoteClusterInfo return the remote cluster info from Elasticsearch, or nil if the remote cluster is not configured
+func getRemoteClusterInfo(ctx context.Context, alias string, meta interface{}) (*RemoteClusterInfo, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Cluster.RemoteInfo(
+		client.API.Cluster.RemoteInfo.WithContext(ctx),
+		client.API.Cluster.RemoteInfo.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("Error when get remote clusters info: %s", res.String())
+	}
+
+	remoteClusters := make(RemoteClustersInfo)
+	if err := json.NewDecoder(res.Body).Decode(&remoteClusters); err != nil {
+		return nil, err
+	}
+
+	return remoteClusters[alias], nil
+}
+
+// checkRemoteCluster return error if the remote cluster is not configured on cluster settings or not connected
+func checkRemoteCluster(ctx context.Context, alias string, meta interface{}) error {
+	remoteCluster, err := getRemoteClusterInfo(ctx, alias, meta)
+	if err != nil {
+		return err
+	}
+	if remoteCluster == nil {
+		return errors.Errorf("Remote cluster %s not found, you need to configure it on cluster settings `cluster.remote.%s`", alias, alias)
+	}
+	if !remoteCluster.Connected {
+		return errors.Errorf("Remote cluster %s is not connected, check that it's reachable from the cluster", alias)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchRemoteCluster(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchRemoteClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchRemoteCluster,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchRemoteClusterExists("elasticsearch_remote_cluster.test"),
+					resource.TestCheckResourceAttr("elasticsearch_remote_cluster.test", "mode", "sniff"),
+					resource.TestCheckResourceAttr("elasticsearch_remote_cluster.test", "seeds.#", "1"),
+				),
+			},
+			{
+				Config: testElasticsearchRemoteClusterUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchRemoteClusterExists("elasticsearch_remote_cluster.test"),
+					resource.TestCheckResourceAttr("elasticsearch_remote_cluster.test", "mode", "proxy"),
+					resource.TestCheckResourceAttr("elasticsearch_remote_cluster.test", "seeds.#", "0"),
+					resource.TestCheckResourceAttr("elasticsearch_remote_cluster.test", "skip_unavailable", "true"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_remote_cluster.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"connected", "num_nodes_connected", "num_proxy_sockets_connected"},
+			},
+		},
+	})
+}
+
+func TestRemoteClusterSettings(t *testing.T) {
+	remoteCluster := &RemoteClusterSpec{
+		Mode:            "sniff",
+		Seeds:           []string{"127.0.0.1:9300"},
+		NodeConnections: 3,
+		SkipUnavailable: true,
+	}
+
+	settings := buildRemoteClusterSettings("test", remoteCluster)
+	expected := map[string]interface{}{
+		"cluster.remote.test.mode":                     nil,
+		"cluster.remote.test.seeds":                    []string{"127.0.0.1:9300"},
+		"cluster.remote.test.node_connections":         3,
+		"cluster.remote.test.proxy_address":            nil,
+		"cluster.remote.test.proxy_socket_connections": nil,
+		"cluster.remote.test.server_name":              nil,
+		"cluster.remote.test.skip_unavailable":         true,
+	}
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("Remote cluster settings must be %+v, got %+v", expected, settings)
+	}
+
+	// Flat settings returned by Elasticsearch are string
+	result, err := parseRemoteClusterSettings("test", map[string]interface{}{
+		"cluster.remote.test.seeds":            []interface{}{"127.0.0.1:9300"},
+		"cluster.remote.test.node_connections": "3",
+		"cluster.remote.test.skip_unavailable": "true",
+		"cluster.remote.other.seeds":           []interface{}{"127.0.0.2:9300"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(result, remoteCluster) {
+		t.Errorf("Remote cluster must be %+v, got %+v", remoteCluster, result)
+	}
+
+	result, err = parseRemoteClusterSettings("test", map[string]interface{}{
+		"cluster.remote.other.seeds": []interface{}{"127.0.0.2:9300"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if result != nil {
+		t.Errorf("Remote cluster must not be found, got %+v", result)
+	}
+}
+
+func TestRemoteClusterModeFields(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		failed bool
+	}{
+		{map[string]interface{}{"name": "test", "seeds": []interface{}{"127.0.0.1:9300"}, "node_connections": 3}, false},
+		{map[string]interface{}{"name": "test", "mode": "proxy", "proxy_address": "127.0.0.1:9400", "proxy_socket_connections": 18, "server_name": "leader"}, false},
+		{map[string]interface{}{"name": "test", "proxy_address": "127.0.0.1:9400"}, true},
+		{map[string]interface{}{"name": "test", "mode": "sniff", "server_name": "leader"}, true},
+		{map[string]interface{}{"name": "test", "mode": "proxy", "proxy_address": "127.0.0.1:9400", "seeds": []interface{}{"127.0.0.1:9300"}}, true},
+		{map[string]interface{}{"name": "test", "mode": "proxy", "proxy_address": "127.0.0.1:9400", "node_connections": 3}, true},
+	}
+
+	r := resourceElasticsearchRemoteCluster()
+	for _, test := range tests {
+		_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(test.config), nil)
+		if (err != nil) != test.failed {
+			t.Errorf("Config %+v must failed: %t, got %v", test.config, test.failed, err)
+		}
+	}
+}
+
+func testCheckElasticsearchRemoteClusterExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No remote cluster ID is set")
+		}
+
+		remoteCluster, err := getRemoteClusterInfo(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if remoteCluster == nil {
+			return errors.Errorf("Remote cluster %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchRemoteClusterDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_remote_cluster" {
+			continue
+		}
+
+		remoteCluster, err := getRemoteClusterInfo(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if remoteCluster != nil {
+			return fmt.Errorf("Remote cluster %q still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testElasticsearchRemoteCluster = `
+resource "elasticsearch_remote_cluster" "test" {
+  name  = "terraform-test"
+  seeds = ["127.0.0.1:9300"]
+}
+`
+
+var testElasticsearchRemoteClusterUpdate = `
+resource "elasticsearch_remote_cluster" "test" {
+  name             = "terraform-test"
+  mode             = "proxy"
+  proxy_address    = "127.0.0.1:9300"
+  skip_unavailable = true
+}
+`
@@ -0,0 +1,367 @@
+// Manage the rollup job in elasticsearch
+// Rollup job can't be updated, so any change create new rollup job, except the started state
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/rollup-apis.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// RollupJobs is the rollup jobs object returned by API
+type RollupJobs struct {
+	Jobs []*RollupJob `json:"jobs"`
+}
+
+// RollupJob is the rollup job returned by API
+type RollupJob struct {
+	Config *RollupJobSpec   `json:"config"`
+	Status *RollupJobStatus `json:"status"`
+}
+
+// RollupJobSpec is the rollup job specification
+type RollupJobSpec struct {
+	ID           string      `json:"id,omitempty"`
+	IndexPattern string      `json:"index_pattern"`
+	RollupIndex  string      `json:"rollup_index"`
+	Cron         string      `json:"cron"`
+	Groups       interface{} `json:"groups"`
+	Metrics      interface{} `json:"metrics,omitempty"`
+	PageSize     int         `json:"page_size"`
+	Timeout      string      `json:"timeout,omitempty"`
+}
+
+// RollupJobStatus is the rollup job status
+type RollupJobStatus struct {
+	JobState string `json:"job_state"`
+}
+
+// resourceElasticsearchRollupJob handle the rollup job API call
+func resourceElasticsearchRollupJob() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchRollupJobCreate,
+		ReadContext:   resourceElasticsearchRollupJobRead,
+		UpdateContext: resourceElasticsearchRollupJobUpdate,
+		DeleteContext: resourceElasticsearchRollupJobDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"index_pattern": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"rollup_index": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"cron": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"groups": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"metrics": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"page_size": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"timeout": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"started": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Start or stop the rollup job",
+			},
+		},
+	}
+}
+
+// resourceElasticsearchRollupJobCreate create new rollup job in Elasticsearch and start it if needed
+func resourceElasticsearchRollupJobCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	rollupJob := &RollupJobSpec{
+		IndexPattern: d.Get("index_pattern").(string),
+		RollupIndex:  d.Get("rollup_index").(string),
+		Cron:         d.Get("cron").(string),
+		Groups:       optionalInterfaceJSON(d.Get("groups").(string)),
+		Metrics:      optionalInterfaceJSON(d.Get("metrics").(string)),
+		PageSize:     d.Get("page_size").(int),
+		Timeout:      d.Get("timeout").(string),
+	}
+	tflog.Debug(ctx, "Rollup job", "rollup_job", rollupJob.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Rollup.PutJob(
+		name,
+		bytes.NewReader([]byte(rollupJob.String())),
+		client.API.Rollup.PutJob.WithContext(ctx),
+		client.API.Rollup.PutJob.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when add rollup job %s: %s", name, res.String())
+	}
+
+	d.SetId(name)
+
+	tflog.Info(ctx, "Created rollup job successfully")
+
+	if d.Get("started").(bool) {
+		if err := startRollupJob(ctx, name, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceElasticsearchRollupJobRead(ctx, d, meta)
+}
+
+// resourceElasticsearchRollupJobRead read existing rollup job and its state in Elasticsearch
+func resourceElasticsearchRollupJobRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	rollupJob, err := getRollupJob(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if rollupJob == nil || rollupJob.Config == nil {
+		tflog.Warn(ctx, "Rollup job not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+	config := rollupJob.Config
+
+	tflog.Debug(ctx, "Get rollup job successfully", "rollup_job", config.String())
+
+	d.Set("name", id)
+	d.Set("index_pattern", config.IndexPattern)
+	d.Set("rollup_index", config.RollupIndex)
+	d.Set("cron", config.Cron)
+	d.Set("page_size", config.PageSize)
+	d.Set("timeout", config.Timeout)
+	d.Set("started", rollupJob.Status != nil && isRollupJobStarted(rollupJob.Status.JobState))
+
+	// Elasticsearch add default values on groups, like the time_zone. Keep only the keys from state to avoid recreate the job
+	var flattenGroups string
+	if d.Get("groups").(string) == "" {
+		flattenGroups, err = convertInterfaceToJsonString(config.Groups)
+	} else {
+		flattenGroups, err = filterJSONFromState(d.Get("groups").(string), config.Groups)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("groups", flattenGroups)
+
+	// Elasticsearch return empty list when there are no metrics
+	if metrics, ok := config.Metrics.([]interface{}); ok && len(metrics) == 0 {
+		config.Metrics = nil
+	}
+	flattenMetrics, err := convertInterfaceToJsonString(config.Metrics)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("metrics", flattenMetrics)
+
+	return nil
+}
+
+// resourceElasticsearchRollupJobUpdate start or stop existing rollup job. The other fields can't be updated
+func resourceElasticsearchRollupJobUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	if d.HasChange("started") {
+		if d.Get("started").(bool) {
+			err := startRollupJob(ctx, id, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		} else {
+			err := stopRollupJob(ctx, id, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return resourceElasticsearchRollupJobRead(ctx, d, meta)
+}
+
+// resourceElasticsearchRollupJobDelete stop and delete existing rollup job in Elasticsearch
+func resourceElasticsearchRollupJobDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	rollupJob, err := getRollupJob(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if rollupJob == nil {
+		tflog.Warn(ctx, "Rollup job not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	// Rollup job must be stopped before delete it
+	if rollupJob.Status != nil && rollupJob.Status.JobState != "stopped" {
+		if err := stopRollupJob(ctx, id, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Rollup.DeleteJob(
+		id,
+		client.API.Rollup.DeleteJob.WithContext(ctx),
+		client.API.Rollup.DeleteJob.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Rollup job not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete rollup job %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted rollup job successfully")
+	return nil
+}
+
+// Print RollupJobSpec object as Json string
+func (r *RollupJobSpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// getRollupJob return the rollup job from Elasticsearch, or nil if not found
+func getRollupJob(ctx context.Context, id string, meta interface{}) (*RollupJob, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Rollup.GetJobs(
+		client.API.Rollup.GetJobs.WithJobID(id),
+		client.API.Rollup.GetJobs.WithContext(ctx),
+		client.API.Rollup.GetJobs.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get rollup job %s: %s", id, res.String())
+	}
+
+	rollupJobs := &RollupJobs{}
+	if err := json.NewDecoder(res.Body).Decode(rollupJobs); err != nil {
+		return nil, err
+	}
+	if len(rollupJobs.Jobs) == 0 {
+		return nil, nil
+	}
+
+	return rollupJobs.Jobs[0], nil
+}
+
+// isRollupJobStarted return true if the rollup job state is a started state
+func isRollupJobStarted(state string) bool {
+	switch state {
+	case "started", "indexing":
+		return true
+	default:
+		return false
+	}
+}
+
+// startRollupJob start the rollup job
+func startRollupJob(ctx context.Context, id string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Rollup.StartJob(
+		id,
+		client.API.Rollup.StartJob.WithContext(ctx),
+		client.API.Rollup.StartJob.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when start rollup job %s: %s", id, res.String())
+	}
+
+	tflog.Info(ctx, "Started rollup job successfully")
+
+	return nil
+}
+
+// stopRollupJob stop the rollup job and wait it's stopped
+func stopRollupJob(ctx context.Context, id string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Rollup.StopJob(
+		id,
+		client.API.Rollup.StopJob.WithWaitForCompletion(true),
+		client.API.Rollup.StopJob.WithContext(ctx),
+		client.API.Rollup.StopJob.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when stop rollup job %s: %s", id, res.String())
+	}
+
+	tflog.Info(ctx, "Stopped rollup job successfully")
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchRollupJob(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchRollupJobDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchRollupJob,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchRollupJobExists("elasticsearch_rollup_job.test"),
+					resource.TestCheckResourceAttr("elasticsearch_rollup_job.test", "started", "false"),
+				),
+			},
+			{
+				Config: testElasticsearchRollupJobStarted,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchRollupJobExists("elasticsearch_rollup_job.test"),
+					resource.TestCheckResourceAttr("elasticsearch_rollup_job.test", "started", "true"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_rollup_job.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Elasticsearch add default values on groups when import it
+				ImportStateVerifyIgnore: []string{"groups"},
+			},
+		},
+	})
+}
+
+func TestIsRollupJobStarted(t *testing.T) {
+	tests := []struct {
+		state    string
+		expected bool
+	}{
+		{"started", true},
+		{"indexing", true},
+		{"stopped", false},
+		{"stopping", false},
+		{"aborting", false},
+	}
+
+	for _, test := range tests {
+		if result := isRollupJobStarted(test.state); result != test.expected {
+			t.Errorf("State %s must return %t, got %t", test.state, test.expected, result)
+		}
+	}
+}
+
+func testCheckElasticsearchRollupJobExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No rollup job ID is set")
+		}
+
+		rollupJob, err := getRollupJob(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if rollupJob == nil {
+			return errors.Errorf("Rollup job %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchRollupJobDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_rollup_job" {
+			continue
+		}
+
+		rollupJob, err := getRollupJob(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if rollupJob != nil {
+			return fmt.Errorf("Rollup job %q still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testElasticsearchRollupJob = `
+resource "elasticsearch_index" "test_rollup" {
+  name     = "terraform-test-rollup"
+  mappings = <<EOF
+{
+  "properties": {
+    "@timestamp": { "type": "date" },
+    "node": { "type": "keyword" },
+    "temperature": { "type": "double" }
+  }
+}
+EOF
+  deletion_protection = false
+}
+
+resource "elasticsearch_rollup_job" "test" {
+  name          = "terraform-test"
+  index_pattern = elasticsearch_index.test_rollup.name
+  rollup_index  = "terraform-test-rollup-result"
+  cron          = "*/30 * * * * ?"
+  page_size     = 1000
+  groups        = <<EOF
+{
+  "date_histogram": {
+    "field": "@timestamp",
+    "fixed_interval": "1h",
+    "delay": "7d"
+  },
+  "terms": {
+    "fields": ["node"]
+  }
+}
+EOF
+  metrics       = <<EOF
+[
+  {
+    "field": "temperature",
+    "metrics": ["min", "max", "sum"]
+  }
+]
+EOF
+}
+`
+
+var testElasticsearchRollupJobStarted = `
+resource "elasticsearch_index" "test_rollup" {
+  name     = "terraform-test-rollup"
+  mappings = <<EOF
+{
+  "properties": {
+    "@timestamp": { "type": "date" },
+    "node": { "type": "keyword" },
+    "temperature": { "type": "double" }
+  }
+}
+EOF
+  deletion_protection = false
+}
+
+resource "elasticsearch_rollup_job" "test" {
+  name          = "terraform-test"
+  index_pattern = elasticsearch_index.test_rollup.name
+  rollup_index  = "terraform-test-rollup-result"
+  cron          = "*/30 * * * * ?"
+  page_size     = 1000
+  groups        = <<EOF
+{
+  "date_histogram": {
+    "field": "@timestamp",
+    "fixed_interval": "1h",
+    "delay": "7d"
+  },
+  "terms": {
+    "fields": ["node"]
+  }
+}
+EOF
+  metrics       = <<EOF
+[
+  {
+    "field": "temperature",
+    "metrics": ["min", "max", "sum"]
+  }
+]
+EOF
+  started       = true
+}
+`
@@ -0,0 +1,325 @@
+// Manage the API key in elasticsearch
+// API key can't be updated, so any change create new API key
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-create-api-key.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// APIKeySpec is the API key object to create it
+type APIKeySpec struct {
+	Name            string      `json:"name"`
+	RoleDescriptors Role        `json:"role_descriptors,omitempty"`
+	Expiration      string      `json:"expiration,omitempty"`
+	Metadata        interface{} `json:"metadata,omitempty"`
+}
+
+// APIKeyCreateResponse is the API key object returned when create it
+type APIKeyCreateResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Expiration int64  `json:"expiration,omitempty"`
+	APIKey     string `json:"api_key"`
+	Encoded    string `json:"encoded,omitempty"`
+}
+
+// APIKeys is the API keys object returned by API
+type APIKeys struct {
+	APIKeys []*APIKeyInfo `json:"api_keys"`
+}
+
+// APIKeyInfo is the API key information
+type APIKeyInfo struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Creation    int64       `json:"creation"`
+	Expiration  int64       `json:"expiration,omitempty"`
+	Invalidated bool        `json:"invalidated"`
+	Username    string      `json:"username"`
+	Realm       string      `json:"realm"`
+	Metadata    interface{} `json:"metadata,omitempty"`
+}
+
+// APIKeyInvalidate is the body to invalidate API keys
+type APIKeyInvalidate struct {
+	IDs []string `json:"ids"`
+}
+
+// resourceElasticsearchSecurityAPIKey handle the API key API call
+func resourceElasticsearchSecurityAPIKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchSecurityAPIKeyCreate,
+		ReadContext:   resourceElasticsearchSecurityAPIKeyRead,
+		DeleteContext: resourceElasticsearchSecurityAPIKeyDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"role_descriptors": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"cluster": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"run_as": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"global": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"metadata": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressEquivalentJSON,
+						},
+						"indices":      roleIndicesSchema(),
+						"applications": roleApplicationsSchema(),
+					},
+				},
+			},
+			"expiration": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The expiration time of the API key, like `30d`. Default it never expire",
+			},
+			"metadata": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"api_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"encoded": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The API key encoded as base64 to use on Authorization header",
+			},
+			"expiration_timestamp": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceElasticsearchSecurityAPIKeyCreate create new API key in Elasticsearch
+func resourceElasticsearchSecurityAPIKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	apiKey := &APIKeySpec{
+		Name:            name,
+		RoleDescriptors: buildRoleDescriptors(d.Get("role_descriptors").(*schema.Set).List()),
+		Expiration:      d.Get("expiration").(string),
+		Metadata:        optionalInterfaceJSON(d.Get("metadata").(string)),
+	}
+	tflog.Debug(ctx, "API key", "api_key", apiKey.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.CreateAPIKey(
+		strings.NewReader(apiKey.String()),
+		client.API.Security.CreateAPIKey.WithContext(ctx),
+		client.API.Security.CreateAPIKey.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when create API key %s: %s", name, res.String())
+	}
+
+	apiKeyResponse := &APIKeyCreateResponse{}
+	if err := json.NewDecoder(res.Body).Decode(apiKeyResponse); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Encoded is only returned since Elasticsearch 7.16
+	encoded := apiKeyResponse.Encoded
+	if encoded == "" {
+		encoded = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", apiKeyResponse.ID, apiKeyResponse.APIKey)))
+	}
+
+	d.SetId(apiKeyResponse.ID)
+	d.Set("api_key", apiKeyResponse.APIKey)
+	d.Set("encoded", encoded)
+
+	tflog.Info(ctx, "Created API key successfully", "key_id", d.Id())
+
+	return resourceElasticsearchSecurityAPIKeyRead(ctx, d, meta)
+}
+
+// resourceElasticsearchSecurityAPIKeyRead read existing API key in Elasticsearch
+// The role descriptors can't be read, so they are kept from state
+func resourceElasticsearchSecurityAPIKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.GetAPIKey(
+		client.API.Security.GetAPIKey.WithID(id),
+		client.API.Security.GetAPIKey.WithContext(ctx),
+		client.API.Security.GetAPIKey.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "API key not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when get API key %s: %s", id, res.String())
+	}
+
+	apiKeys := &APIKeys{}
+	if err := json.NewDecoder(res.Body).Decode(apiKeys); err != nil {
+		return diag.FromErr(err)
+	}
+	if len(apiKeys.APIKeys) == 0 {
+		tflog.Warn(ctx, "API key not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+	apiKey := apiKeys.APIKeys[0]
+	if apiKey.Invalidated {
+		tflog.Warn(ctx, "API key is invalidated - removing from state")
+		d.SetId("")
+		return nil
+	}
+	if isAPIKeyExpired(apiKey, time.Now()) {
+		tflog.Warn(ctx, "API key is expired - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	metadata, err := convertInterfaceToJsonString(apiKey.Metadata)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Get API key successfully", "name", apiKey.Name, "username", apiKey.Username, "expiration", apiKey.Expiration)
+
+	d.Set("name", apiKey.Name)
+	d.Set("metadata", metadata)
+	d.Set("expiration_timestamp", apiKey.Expiration)
+
+	return nil
+}
+
+// resourceElasticsearchSecurityAPIKeyDelete invalidate existing API key in Elasticsearch
+func resourceElasticsearchSecurityAPIKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	data, err := json.Marshal(&APIKeyInvalidate{
+		IDs: []string{id},
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.InvalidateAPIKey(
+		bytes.NewReader(data),
+		client.API.Security.InvalidateAPIKey.WithContext(ctx),
+		client.API.Security.InvalidateAPIKey.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "API key not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when invalidate API key %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Invalidated API key successfully")
+	return nil
+}
+
+// Print APIKeySpec object as Json string
+func (r *APIKeySpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// isAPIKeyExpired return true if the API key has expiration in the past
+func isAPIKeyExpired(apiKey *APIKeyInfo, now time.Time) bool {
+	return apiKey.Expiration > 0 && apiKey.Expiration <= now.UnixNano()/int64(time.Millisecond)
+}
+
+// buildRoleDescriptors convert list to role descriptors, with the same shape as role
+func buildRoleDescriptors(raws []interface{}) Role {
+	if len(raws) == 0 {
+		return nil
+	}
+
+	roleDescriptors := make(Role)
+	for _, raw := range raws {
+		m := raw.(map[string]interface{})
+		roleDescriptors[m["name"].(string)] = &RoleSpec{
+			Cluster:      convertArrayInterfaceToArrayString(m["cluster"].(*schema.Set).List()),
+			Applications: buildRolesApplicationPrivileges(m["applications"].(*schema.Set).List()),
+			Indices:      buildRolesIndicesPermissions(m["indices"].(*schema.Set).List()),
+			RunAs:        convertArrayInterfaceToArrayString(m["run_as"].(*schema.Set).List()),
+			Global:       optionalInterfaceJSON(m["global"].(string)),
+			Metadata:     optionalInterfaceJSON(m["metadata"].(string)),
+		}
+	}
+
+	return roleDescriptors
+}
@@ -0,0 +1,165 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchSecurityAPIKey(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchSecurityAPIKeyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchSecurityAPIKey,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityAPIKeyExists("elasticsearch_api_key.test"),
+					resource.TestCheckResourceAttrSet("elasticsearch_api_key.test", "encoded"),
+					resource.TestCheckResourceAttrSet("elasticsearch_api_key.test", "expiration_timestamp"),
+				),
+			},
+		},
+	})
+}
+
+func TestSecurityAPIKeyRead(t *testing.T) {
+	future := time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	past := time.Now().Add(-time.Hour).UnixNano() / int64(time.Millisecond)
+
+	tests := []struct {
+		apiKey   string
+		deleted  bool
+		metadata string
+	}{
+		{fmt.Sprintf(`{"id": "terraform-test", "name": "test", "expiration": %d, "invalidated": false, "metadata": {"team": "ops"}}`, future), false, `{"team":"ops"}`},
+		{`{"id": "terraform-test", "name": "test", "invalidated": false, "metadata": {}}`, false, ""},
+		{fmt.Sprintf(`{"id": "terraform-test", "name": "test", "expiration": %d, "invalidated": false}`, past), true, ""},
+		{`{"id": "terraform-test", "name": "test", "invalidated": true}`, true, ""},
+	}
+
+	for _, test := range tests {
+		meta, server := newTestProviderMeta(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(fmt.Sprintf(`{"api_keys": [%s]}`, test.apiKey)))
+		})
+
+		d := resourceElasticsearchSecurityAPIKey().TestResourceData()
+		d.SetId("terraform-test")
+		diags := resourceElasticsearchSecurityAPIKeyRead(context.Background(), d, meta)
+		server.Close()
+
+		if diags.HasError() {
+			t.Fatalf("err: %+v", diags)
+		}
+		if (d.Id() == "") != test.deleted {
+			t.Errorf("API key %s must be removed from state: %t", test.apiKey, test.deleted)
+		}
+		if !test.deleted && d.Get("metadata").(string) != test.metadata {
+			t.Errorf("API key %s must have metadata %s, got %s", test.apiKey, test.metadata, d.Get("metadata"))
+		}
+	}
+}
+
+func testCheckElasticsearchSecurityAPIKeyExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No API key ID is set")
+		}
+
+		apiKey, err := testGetAPIKey(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if apiKey == nil || apiKey.Invalidated {
+			return errors.Errorf("API key %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchSecurityAPIKeyDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_api_key" {
+			continue
+		}
+
+		apiKey, err := testGetAPIKey(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if apiKey != nil && !apiKey.Invalidated {
+			return fmt.Errorf("API key %q is still valid", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testGetAPIKey(id string) (*APIKeyInfo, error) {
+	meta := testAccProvider.Meta()
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.GetAPIKey(
+		client.API.Security.GetAPIKey.WithID(id),
+		client.API.Security.GetAPIKey.WithContext(context.Background()),
+		client.API.Security.GetAPIKey.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get API key %s: %s", id, res.String())
+	}
+
+	apiKeys := &APIKeys{}
+	if err := json.NewDecoder(res.Body).Decode(apiKeys); err != nil {
+		return nil, err
+	}
+	if len(apiKeys.APIKeys) == 0 {
+		return nil, nil
+	}
+
+	return apiKeys.APIKeys[0], nil
+}
+
+var testElasticsearchSecurityAPIKey = `
+resource "elasticsearch_api_key" "test" {
+  name       = "terraform-test"
+  expiration = "1d"
+
+  role_descriptors {
+    name    = "read-logs"
+    cluster = ["monitor"]
+    indices {
+      names      = ["logs-*"]
+      privileges = ["read"]
+    }
+  }
+
+  metadata = <<EOF
+{
+	"owner": "terraform"
+}
+EOF
+}
+`
@@ -0,0 +1,243 @@
+// Manage the application privileges in elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-put-privileges.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// ApplicationPrivileges is the application privileges object returned by API
+// The first key is the application name and the second key is the privilege name
+type ApplicationPrivileges map[string]map[string]*ApplicationPrivilegeSpec
+
+// ApplicationPrivilegeSpec is the application privilege specification
+type ApplicationPrivilegeSpec struct {
+	Application string      `json:"application,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Actions     []string    `json:"actions"`
+	Metadata    interface{} `json:"metadata,omitempty"`
+}
+
+// resourceElasticsearchSecurityApplicationPrivileges handle the application privileges API call
+func resourceElasticsearchSecurityApplicationPrivileges() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchSecurityApplicationPrivilegesCreate,
+		ReadContext:   resourceElasticsearchSecurityApplicationPrivilegesRead,
+		UpdateContext: resourceElasticsearchSecurityApplicationPrivilegesUpdate,
+		DeleteContext: resourceElasticsearchSecurityApplicationPrivilegesDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"application": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"actions": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"metadata": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+		},
+	}
+}
+
+// resourceElasticsearchSecurityApplicationPrivilegesCreate create new application privileges in Elasticsearch
+func resourceElasticsearchSecurityApplicationPrivilegesCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := fmt.Sprintf("%s/%s", d.Get("application").(string), d.Get("name").(string))
+	ctx = withLogID(ctx, id)
+
+	err := createApplicationPrivileges(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(id)
+
+	tflog.Info(ctx, "Created application privileges successfully")
+
+	return resourceElasticsearchSecurityApplicationPrivilegesRead(ctx, d, meta)
+}
+
+// resourceElasticsearchSecurityApplicationPrivilegesRead read existing application privileges in Elasticsearch
+func resourceElasticsearchSecurityApplicationPrivilegesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	application, name, err := parseApplicationPrivilegesID(id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.GetPrivileges(
+		client.API.Security.GetPrivileges.WithApplication(application),
+		client.API.Security.GetPrivileges.WithName(name),
+		client.API.Security.GetPrivileges.WithContext(ctx),
+		client.API.Security.GetPrivileges.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Application privileges not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when get application privileges %s: %s", id, res.String())
+	}
+
+	privileges := make(ApplicationPrivileges)
+	if err := json.NewDecoder(res.Body).Decode(&privileges); err != nil {
+		return diag.FromErr(err)
+	}
+	if privileges[application] == nil || privileges[application][name] == nil {
+		tflog.Warn(ctx, "Application privileges not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+	privilege := privileges[application][name]
+
+	tflog.Debug(ctx, "Get application privileges successfully", "actions", privilege.Actions)
+
+	d.Set("application", application)
+	d.Set("name", name)
+	d.Set("actions", privilege.Actions)
+
+	flattenMetadata, err := convertInterfaceToJsonString(privilege.Metadata)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("metadata", flattenMetadata)
+
+	return nil
+}
+
+// resourceElasticsearchSecurityApplicationPrivilegesUpdate update existing application privileges in Elasticsearch
+func resourceElasticsearchSecurityApplicationPrivilegesUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createApplicationPrivileges(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "Updated application privileges successfully")
+
+	return resourceElasticsearchSecurityApplicationPrivilegesRead(ctx, d, meta)
+}
+
+// resourceElasticsearchSecurityApplicationPrivilegesDelete delete existing application privileges in Elasticsearch
+func resourceElasticsearchSecurityApplicationPrivilegesDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	application, name, err := parseApplicationPrivilegesID(id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.DeletePrivileges(
+		name,
+		application,
+		client.API.Security.DeletePrivileges.WithContext(ctx),
+		client.API.Security.DeletePrivileges.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Application privileges not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete application privileges %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted application privileges successfully")
+	return nil
+}
+
+// Print ApplicationPrivileges object as Json string
+func (r ApplicationPrivileges) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// createApplicationPrivileges create or update application privileges in Elasticsearch
+func createApplicationPrivileges(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	application := d.Get("application").(string)
+	name := d.Get("name").(string)
+
+	privileges := ApplicationPrivileges{
+		application: {
+			name: &ApplicationPrivilegeSpec{
+				Actions:  convertArrayInterfaceToArrayString(d.Get("actions").(*schema.Set).List()),
+				Metadata: optionalInterfaceJSON(d.Get("metadata").(string)),
+			},
+		},
+	}
+	tflog.Debug(ctx, "Application privileges", "privileges", privileges.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.PutPrivileges(
+		bytes.NewReader([]byte(privileges.String())),
+		client.API.Security.PutPrivileges.WithContext(ctx),
+		client.API.Security.PutPrivileges.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when add application privileges %s/%s: %s", application, name, res.String())
+	}
+
+	return nil
+}
+
+// parseApplicationPrivilegesID return the application and the privilege name from ID
+func parseApplicationPrivilegesID(id string) (application string, name string, err error) {
+	ids := strings.SplitN(id, "/", 2)
+	if len(ids) != 2 || ids[0] == "" || ids[1] == "" {
+		return "", "", errors.Errorf("Application privileges ID %s must be application/name", id)
+	}
+
+	return ids[0], ids[1], nil
+}
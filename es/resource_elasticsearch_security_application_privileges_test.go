@@ -0,0 +1,135 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchSecurityApplicationPrivileges(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchSecurityApplicationPrivilegesDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchSecurityApplicationPrivileges,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityApplicationPrivilegesExists("elasticsearch_application_privileges.test"),
+				),
+			},
+			{
+				Config: testElasticsearchSecurityApplicationPrivilegesUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityApplicationPrivilegesExists("elasticsearch_application_privileges.test"),
+					resource.TestCheckResourceAttr("elasticsearch_application_privileges.test", "actions.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_application_privileges.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchSecurityApplicationPrivilegesExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No application privileges ID is set")
+		}
+
+		application, privilege, err := parseApplicationPrivilegesID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Security.GetPrivileges(
+			client.API.Security.GetPrivileges.WithApplication(application),
+			client.API.Security.GetPrivileges.WithName(privilege),
+			client.API.Security.GetPrivileges.WithContext(context.Background()),
+			client.API.Security.GetPrivileges.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when get application privileges %s: %s", rs.Primary.ID, res.String())
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchSecurityApplicationPrivilegesDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_application_privileges" {
+			continue
+		}
+
+		application, privilege, err := parseApplicationPrivilegesID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.Security.GetPrivileges(
+			client.API.Security.GetPrivileges.WithApplication(application),
+			client.API.Security.GetPrivileges.WithName(privilege),
+			client.API.Security.GetPrivileges.WithContext(context.Background()),
+			client.API.Security.GetPrivileges.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			if res.StatusCode == 404 {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Application privileges %q still exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+var testElasticsearchSecurityApplicationPrivileges = `
+resource "elasticsearch_application_privileges" "test" {
+  application = "terraform-test"
+  name        = "read"
+  actions     = ["data:read/*"]
+}
+`
+
+var testElasticsearchSecurityApplicationPrivilegesUpdate = `
+resource "elasticsearch_application_privileges" "test" {
+  application = "terraform-test"
+  name        = "read"
+  actions     = ["data:read/*", "action:login"]
+  metadata    = <<EOF
+{
+	"description": "Read access"
+}
+EOF
+}
+`
@@ -0,0 +1,260 @@
+// Manage the password and the enabled flag of reserved user in elasticsearch, like kibana_system
+// The user is never deleted, because reserved users can't be deleted
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-change-password.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// resourceElasticsearchSecurityBuiltinUserPassword handle the change password and enable / disable user API call
+func resourceElasticsearchSecurityBuiltinUserPassword() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchSecurityBuiltinUserPasswordCreate,
+		ReadContext:   resourceElasticsearchSecurityBuiltinUserPasswordRead,
+		UpdateContext: resourceElasticsearchSecurityBuiltinUserPasswordUpdate,
+		DeleteContext: resourceElasticsearchSecurityBuiltinUserPasswordDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"password": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"password_hash"},
+			},
+			"password_hash": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"password"},
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+// resourceElasticsearchSecurityBuiltinUserPasswordCreate set the password of reserved user in Elasticsearch
+func resourceElasticsearchSecurityBuiltinUserPasswordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	username := d.Get("username").(string)
+	ctx = withLogID(ctx, username)
+
+	user, err := getUser(ctx, username, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if user == nil {
+		return diag.Errorf("User %s not found", username)
+	}
+	if !isReservedUser(user) {
+		return diag.Errorf("User %s is not reserved user, you need to use elasticsearch_user resource", username)
+	}
+
+	if err := changeUserPassword(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	if d.Get("enabled").(bool) != user.Enabled {
+		if err := enableUser(ctx, username, d.Get("enabled").(bool), meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(username)
+
+	tflog.Info(ctx, "Set reserved user password successfully")
+
+	return resourceElasticsearchSecurityBuiltinUserPasswordRead(ctx, d, meta)
+}
+
+// resourceElasticsearchSecurityBuiltinUserPasswordRead read the reserved user in Elasticsearch
+// The password can't be read, so it's kept from state
+func resourceElasticsearchSecurityBuiltinUserPasswordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	user, err := getUser(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if user == nil {
+		tflog.Warn(ctx, "User not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("username", id)
+	d.Set("enabled", user.Enabled)
+
+	tflog.Info(ctx, "Read reserved user successfully")
+
+	return nil
+}
+
+// resourceElasticsearchSecurityBuiltinUserPasswordUpdate update the password or the enabled flag of reserved user in Elasticsearch
+func resourceElasticsearchSecurityBuiltinUserPasswordUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	if d.HasChange("password") || d.HasChange("password_hash") {
+		if err := changeUserPassword(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("enabled") {
+		if err := enableUser(ctx, id, d.Get("enabled").(bool), meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	tflog.Info(ctx, "Updated reserved user successfully")
+
+	return resourceElasticsearchSecurityBuiltinUserPasswordRead(ctx, d, meta)
+}
+
+// resourceElasticsearchSecurityBuiltinUserPasswordDelete only remove reserved user from state, the user is left intact
+func resourceElasticsearchSecurityBuiltinUserPasswordDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	tflog.Info(ctx, "Reserved user can't be deleted, it's only removed from state")
+
+	d.SetId("")
+	return nil
+}
+
+// getUser return the user from Elasticsearch, or nil if not found
+func getUser(ctx context.Context, username string, meta interface{}) (*UserSpec, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.GetUser(
+		client.API.Security.GetUser.WithUsername(username),
+		client.API.Security.GetUser.WithContext(ctx),
+		client.API.Security.GetUser.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get user %s: %s", username, res.String())
+	}
+
+	user := make(User)
+	if err := json.NewDecoder(res.Body).Decode(&user); err != nil {
+		return nil, err
+	}
+
+	return user[username], nil
+}
+
+// isReservedUser return true if user is reserved user, like elastic or kibana_system
+func isReservedUser(user *UserSpec) bool {
+	metadata, ok := user.Metadata.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	reserved, ok := metadata["_reserved"].(bool)
+
+	return ok && reserved
+}
+
+// changeUserPassword change the user password with password or password hash, if they are set
+func changeUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	username := d.Get("username").(string)
+
+	payload := make(map[string]string)
+	if password := d.Get("password").(string); password != "" {
+		payload["password"] = password
+	} else if passwordHash := d.Get("password_hash").(string); passwordHash != "" {
+		payload["password_hash"] = passwordHash
+	} else {
+		return nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.ChangePassword(
+		bytes.NewReader(data),
+		client.API.Security.ChangePassword.WithUsername(username),
+		client.API.Security.ChangePassword.WithContext(ctx),
+		client.API.Security.ChangePassword.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when change password for user %s: %s", username, res.String())
+	}
+
+	tflog.Info(ctx, "Updated user password successfully")
+
+	return nil
+}
+
+// enableUser enable or disable the user
+func enableUser(ctx context.Context, username string, enabled bool, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+
+	if enabled {
+		res, err := client.API.Security.EnableUser(
+			username,
+			client.API.Security.EnableUser.WithContext(ctx),
+			client.API.Security.EnableUser.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when enable user %s: %s", username, res.String())
+		}
+	} else {
+		res, err := client.API.Security.DisableUser(
+			username,
+			client.API.Security.DisableUser.WithContext(ctx),
+			client.API.Security.DisableUser.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when disable user %s: %s", username, res.String())
+		}
+	}
+
+	tflog.Info(ctx, "Updated user enabled flag successfully", "enabled", enabled)
+
+	return nil
+}
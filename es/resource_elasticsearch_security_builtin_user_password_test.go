@@ -0,0 +1,105 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchSecurityBuiltinUserPassword(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchSecurityBuiltinUserPasswordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testElasticsearchSecurityBuiltinUserPasswordNotReserved,
+				ExpectError: regexp.MustCompile("is not reserved user|not found"),
+			},
+			{
+				Config: testElasticsearchSecurityBuiltinUserPassword,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityBuiltinUserPasswordExists("elasticsearch_builtin_user_password.test"),
+				),
+			},
+			{
+				Config: testElasticsearchSecurityBuiltinUserPasswordUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityBuiltinUserPasswordExists("elasticsearch_builtin_user_password.test"),
+					resource.TestCheckResourceAttr("elasticsearch_builtin_user_password.test", "enabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchSecurityBuiltinUserPasswordExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No user ID is set")
+		}
+
+		user, err := getUser(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return errors.Errorf("User %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+// testCheckElasticsearchSecurityBuiltinUserPasswordDestroy check that reserved user is left intact
+func testCheckElasticsearchSecurityBuiltinUserPasswordDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_builtin_user_password" {
+			continue
+		}
+
+		user, err := getUser(context.Background(), rs.Primary.ID, testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return fmt.Errorf("Reserved user %q must not be deleted", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testElasticsearchSecurityBuiltinUserPasswordNotReserved = `
+resource "elasticsearch_builtin_user_password" "test" {
+  username = "terraform-test-not-reserved"
+  password = "changeme"
+}
+`
+
+var testElasticsearchSecurityBuiltinUserPassword = `
+resource "elasticsearch_builtin_user_password" "test" {
+  username = "logstash_system"
+  password = "changeme"
+}
+`
+
+var testElasticsearchSecurityBuiltinUserPasswordUpdate = `
+resource "elasticsearch_builtin_user_password" "test" {
+  username = "logstash_system"
+  password = "changeme2"
+  enabled  = false
+}
+`
@@ -3,6 +3,7 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
@@ -14,10 +15,10 @@ import (
 	"io/ioutil"
 	"reflect"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // Role object returned by API
@@ -51,13 +52,14 @@ type RoleIndicesPermissions struct {
 // resourceElasticsearchSecurityRole handle the role API call
 func resourceElasticsearchSecurityRole() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchSecurityRoleCreate,
-		Read:   resourceElasticsearchSecurityRoleRead,
-		Update: resourceElasticsearchSecurityRoleUpdate,
-		Delete: resourceElasticsearchSecurityRoleDelete,
+		CreateContext: resourceElasticsearchSecurityRoleCreate,
+		ReadContext:   resourceElasticsearchSecurityRoleRead,
+		UpdateContext: resourceElasticsearchSecurityRoleUpdate,
+		DeleteContext: resourceElasticsearchSecurityRoleDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -89,61 +91,71 @@ func resourceElasticsearchSecurityRole() *schema.Resource {
 				Optional:         true,
 				DiffSuppressFunc: suppressEquivalentJSON,
 			},
-			"indices": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"names": {
-							Type:     schema.TypeSet,
-							Required: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"privileges": {
-							Type:     schema.TypeSet,
-							Required: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"query": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							DiffSuppressFunc: suppressEquivalentJSON,
-						},
-						"field_security": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							DiffSuppressFunc: suppressEquivalentJSON,
-						},
+			"indices":      roleIndicesSchema(),
+			"applications": roleApplicationsSchema(),
+		},
+	}
+}
+
+// roleIndicesSchema return the schema of indices permissions, shared by role and API key
+func roleIndicesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"names": {
+					Type:     schema.TypeSet,
+					Required: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"privileges": {
+					Type:     schema.TypeSet,
+					Required: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
 					},
 				},
+				"query": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					DiffSuppressFunc: suppressEquivalentJSON,
+				},
+				"field_security": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					DiffSuppressFunc: suppressEquivalentJSON,
+				},
 			},
-			"applications": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"application": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"privileges": {
-							Type:     schema.TypeSet,
-							Optional: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"resources": {
-							Type:     schema.TypeSet,
-							Optional: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
+		},
+	}
+}
+
+// roleApplicationsSchema return the schema of applications privileges, shared by role and API key
+func roleApplicationsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"application": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"privileges": {
+					Type:     schema.TypeSet,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"resources": {
+					Type:     schema.TypeSet,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
 					},
 				},
 			},
@@ -152,131 +164,129 @@ func resourceElasticsearchSecurityRole() *schema.Resource {
 }
 
 // resourceElasticsearchSecurityRoleCreate create new role in Elasticsearch
-func resourceElasticsearchSecurityRoleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
 
-	err := createRole(d, meta)
+	err := createRole(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(name)
 
-	log.Infof("Created role %s successfully", name)
+	tflog.Info(ctx, "Created role successfully")
 
-	return resourceElasticsearchSecurityRoleRead(d, meta)
+	return resourceElasticsearchSecurityRoleRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSecurityRoleRead read existing role in Elasticsearch
-func resourceElasticsearchSecurityRoleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	log.Debugf("Role id:  %s", id)
-
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Security.GetRole(
-		client.API.Security.GetRole.WithContext(context.Background()),
+		client.API.Security.GetRole.WithContext(ctx),
 		client.API.Security.GetRole.WithPretty(),
 		client.API.Security.GetRole.WithName(id),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Role %s not found - removing from state", id)
-			log.Warnf("Role %s not found - removing from state", id)
+			tflog.Warn(ctx, "Role not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get role %s: %s", id, res.String())
+		return diag.Errorf("Error when get role %s: %s", id, res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get role %s successfully:\n%s", id, string(b))
+	tflog.Debug(ctx, "Get role successfully", "body", string(b))
 	role := make(Role)
 	err = json.Unmarshal(b, &role)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Role %+v", role)
-
 	d.Set("name", id)
 
 	flattenIndices, err := flattenIndicesMapping(role[id].Indices)
 	if err := d.Set("indices", flattenIndices); err != nil {
-		return fmt.Errorf("error setting indices: %w", err)
+		return diag.FromErr(fmt.Errorf("error setting indices: %w", err))
 	}
 	d.Set("cluster", role[id].Cluster)
 
 	if err := d.Set("applications", flattenApplicationsMapping(role[id].Applications)); err != nil {
-		return fmt.Errorf("error setting applications: %w", err)
+		return diag.FromErr(fmt.Errorf("error setting applications: %w", err))
 	}
 	d.Set("global", role[id].Global)
 	d.Set("run_as", role[id].RunAs)
 
 	flattenMetdata, err := convertInterfaceToJsonString(role[id].Metadata)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("metadata", flattenMetdata)
 
-	log.Infof("Read role %s successfully", id)
+	tflog.Info(ctx, "Read role successfully")
 
 	return nil
 }
 
 // resourceElasticsearchSecurityRoleUpdate update existing role in Elasticsearch
-func resourceElasticsearchSecurityRoleUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createRole(d, meta)
+func resourceElasticsearchSecurityRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createRole(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Infof("Updated role %s successfully", d.Id())
+	tflog.Info(ctx, "Updated role successfully")
 
-	return resourceElasticsearchSecurityRoleRead(d, meta)
+	return resourceElasticsearchSecurityRoleRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSecurityRoleDelete delete existing role in Elasticsearch
-func resourceElasticsearchSecurityRoleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
-	log.Debugf("Role id: %s", id)
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Security.DeleteRole(
 		id,
-		client.API.Security.DeleteRole.WithContext(context.Background()),
+		client.API.Security.DeleteRole.WithContext(ctx),
 		client.API.Security.DeleteRole.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Role %s not found - removing from state", id)
-			log.Warnf("Role %s not found - removing from state", id)
+			tflog.Warn(ctx, "Role not found - removing from state")
 			d.SetId("")
 			return nil
 
 		}
-		return errors.Errorf("Error when delete role %s: %s", id, res.String())
+		return diag.Errorf("Error when delete role %s: %s", id, res.String())
 	}
 
 	d.SetId("")
 
-	log.Infof("Deleted role %s successfully", id)
+	tflog.Info(ctx, "Deleted role successfully")
 	return nil
 
 }
@@ -288,7 +298,7 @@ func (r *RoleSpec) String() string {
 }
 
 // createRole create or update role in Elasticsearch
-func createRole(d *schema.ResourceData, meta interface{}) error {
+func createRole(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	indices := buildRolesIndicesPermissions(d.Get("indices").(*schema.Set).List())
 	applications := buildRolesApplicationPrivileges(d.Get("applications").(*schema.Set).List())
@@ -305,19 +315,18 @@ func createRole(d *schema.ResourceData, meta interface{}) error {
 		Global:       global,
 		Metadata:     metadata,
 	}
-	log.Debug("Name: ", name)
-	log.Debug("Role: ", role)
+	tflog.Debug(ctx, "Role", "role", role.String())
 
 	data, err := json.Marshal(role)
 	if err != nil {
 		return err
 	}
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Security.PutRole(
 		name,
 		bytes.NewReader(data),
-		client.API.Security.PutRole.WithContext(context.Background()),
+		client.API.Security.PutRole.WithContext(ctx),
 		client.API.Security.PutRole.WithPretty(),
 	)
 
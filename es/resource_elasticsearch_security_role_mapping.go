@@ -3,6 +3,7 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
@@ -10,13 +11,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // RoleMapping is role mapping object returned by API
@@ -33,13 +33,14 @@ type RoleMappingSpec struct {
 // resourceElasticsearchSecurityRoleMapping handle role mapping API call
 func resourceElasticsearchSecurityRoleMapping() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchSecurityRoleMappingCreate,
-		Read:   resourceElasticsearchSecurityRoleMappingRead,
-		Update: resourceElasticsearchSecurityRoleMappingUpdate,
-		Delete: resourceElasticsearchSecurityRoleMappingDelete,
+		CreateContext: resourceElasticsearchSecurityRoleMappingCreate,
+		ReadContext:   resourceElasticsearchSecurityRoleMappingRead,
+		UpdateContext: resourceElasticsearchSecurityRoleMappingUpdate,
+		DeleteContext: resourceElasticsearchSecurityRoleMappingDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -75,129 +76,127 @@ func resourceElasticsearchSecurityRoleMapping() *schema.Resource {
 }
 
 // resourceElasticsearchSecurityRoleMappingCreate  create new role mapping in Elasticsearch
-func resourceElasticsearchSecurityRoleMappingCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityRoleMappingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
 
-	err := createRoleMapping(d, meta)
+	err := createRoleMapping(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(name)
-	log.Infof("Created role mapping %s successfully", name)
+	tflog.Info(ctx, "Created role mapping successfully")
 
-	return resourceElasticsearchSecurityRoleMappingRead(d, meta)
+	return resourceElasticsearchSecurityRoleMappingRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSecurityRoleMappingRead read existing role mapping in Elasticsearch
-func resourceElasticsearchSecurityRoleMappingRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityRoleMappingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	log.Debugf("Role mapping id:  %s", id)
-
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Security.GetRoleMapping(
-		client.API.Security.GetRoleMapping.WithContext(context.Background()),
+		client.API.Security.GetRoleMapping.WithContext(ctx),
 		client.API.Security.GetRoleMapping.WithPretty(),
 		client.API.Security.GetRoleMapping.WithName(id),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Role mapping %s not found. Removing from state\n", id)
-			log.Warnf("Role mapping %s not found. Removing from state\n", id)
+			tflog.Warn(ctx, "Role mapping not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get role mapping %s: %s", id, res.String())
+		return diag.Errorf("Error when get role mapping %s: %s", id, res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get role mapping %s successfully:\n%s", id, string(b))
+	tflog.Debug(ctx, "Get role mapping successfully", "body", string(b))
 	roleMapping := make(RoleMapping)
 	err = json.Unmarshal(b, &roleMapping)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Role mapping %+v", roleMapping)
-
 	d.Set("name", id)
 	d.Set("enabled", roleMapping[id].Enabled)
 	d.Set("roles", roleMapping[id].Roles)
 	flattenRules, err := convertInterfaceToJsonString(roleMapping[id].Rules)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("rules", flattenRules)
 	flattenMetadata, err := convertInterfaceToJsonString(roleMapping[id].Metadata)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("metadata", flattenMetadata)
 
-	log.Infof("Read role mapping %s successfully", id)
+	tflog.Info(ctx, "Read role mapping successfully")
 	return nil
 }
 
 // resourceElasticsearchSecurityRoleMappingUpdate update existing role mapping in Elasticsearch
-func resourceElasticsearchSecurityRoleMappingUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createRoleMapping(d, meta)
+func resourceElasticsearchSecurityRoleMappingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createRoleMapping(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Infof("Updated role mapping %s successfully", d.Id())
+	tflog.Info(ctx, "Updated role mapping successfully")
 
-	return resourceElasticsearchSecurityRoleMappingRead(d, meta)
+	return resourceElasticsearchSecurityRoleMappingRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSecurityRoleMappingDelete delete existing role mapping in Elasticsearch
-func resourceElasticsearchSecurityRoleMappingDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityRoleMappingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
-	log.Debugf("Role mapping id: %s", id)
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Security.DeleteRoleMapping(
 		id,
-		client.API.Security.DeleteRoleMapping.WithContext(context.Background()),
+		client.API.Security.DeleteRoleMapping.WithContext(ctx),
 		client.API.Security.DeleteRoleMapping.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Role mapping %s not found - removing from state", id)
-			log.Warnf("Role mapping %s not found - removing from state", id)
+			tflog.Warn(ctx, "Role mapping not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when delete role mapping %s: %s", id, res.String())
+		return diag.Errorf("Error when delete role mapping %s: %s", id, res.String())
 
 	}
 
 	d.SetId("")
 
-	log.Infof("Deleted role mapping %s successfully", id)
+	tflog.Info(ctx, "Deleted role mapping successfully")
 	return nil
 
 }
 
 // createRoleMapping create or update role mapping
-func createRoleMapping(d *schema.ResourceData, meta interface{}) error {
+func createRoleMapping(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	enabled := d.Get("enabled").(bool)
 	roles := convertArrayInterfaceToArrayString(d.Get("roles").(*schema.Set).List())
@@ -210,19 +209,18 @@ func createRoleMapping(d *schema.ResourceData, meta interface{}) error {
 		Rules:    rules,
 		Metadata: metadata,
 	}
-	log.Debug("Name: ", name)
-	log.Debug("RoleMapping: ", roleMapping)
+	tflog.Debug(ctx, "Role mapping", "role_mapping", roleMapping.String())
 
 	data, err := json.Marshal(roleMapping)
 	if err != nil {
 		return err
 	}
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Security.PutRoleMapping(
 		name,
 		bytes.NewReader(data),
-		client.API.Security.PutRoleMapping.WithContext(context.Background()),
+		client.API.Security.PutRoleMapping.WithContext(ctx),
 		client.API.Security.PutRoleMapping.WithPretty(),
 	)
 
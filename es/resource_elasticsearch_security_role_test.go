@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"testing"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -53,7 +52,7 @@ func testCheckElasticsearchSecurityRoleExists(name string) resource.TestCheckFun
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Security.GetRole(
 			client.API.Security.GetRole.WithContext(context.Background()),
 			client.API.Security.GetRole.WithPretty(),
@@ -79,7 +78,7 @@ func testCheckElasticsearchSecurityRoleDestroy(s *terraform.State) error {
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Security.GetRole(
 			client.API.Security.GetRole.WithContext(context.Background()),
 			client.API.Security.GetRole.WithPretty(),
@@ -0,0 +1,192 @@
+// Manage the service account token in elasticsearch
+// Token can't be updated, so any change create new token
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-create-service-token.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ServiceAccountTokenCreateResponse is the service account token object returned when create it
+type ServiceAccountTokenCreateResponse struct {
+	Created bool                     `json:"created"`
+	Token   *ServiceAccountTokenSpec `json:"token"`
+}
+
+// ServiceAccountTokenSpec is the service account token
+type ServiceAccountTokenSpec struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ServiceAccountCredentials is the credentials of service account returned by API
+type ServiceAccountCredentials struct {
+	ServiceAccount string                 `json:"service_account"`
+	Count          int                    `json:"count"`
+	Tokens         map[string]interface{} `json:"tokens"`
+}
+
+// resourceElasticsearchSecurityServiceAccountToken handle the service account token API call
+func resourceElasticsearchSecurityServiceAccountToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchSecurityServiceAccountTokenCreate,
+		ReadContext:   resourceElasticsearchSecurityServiceAccountTokenRead,
+		DeleteContext: resourceElasticsearchSecurityServiceAccountTokenDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"service": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The token value to use as bearer token",
+			},
+		},
+	}
+}
+
+// resourceElasticsearchSecurityServiceAccountTokenCreate create new service account token in Elasticsearch
+func resourceElasticsearchSecurityServiceAccountTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	namespace := d.Get("namespace").(string)
+	service := d.Get("service").(string)
+	name := d.Get("name").(string)
+	id := fmt.Sprintf("%s/%s/%s", namespace, service, name)
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.CreateServiceToken(
+		namespace,
+		service,
+		client.API.Security.CreateServiceToken.WithName(name),
+		client.API.Security.CreateServiceToken.WithContext(ctx),
+		client.API.Security.CreateServiceToken.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when create service account token %s: %s", id, res.String())
+	}
+
+	token := &ServiceAccountTokenCreateResponse{}
+	if err := json.NewDecoder(res.Body).Decode(token); err != nil {
+		return diag.FromErr(err)
+	}
+	if token.Token == nil {
+		return diag.Errorf("Error when create service account token %s: no token returned", id)
+	}
+
+	d.SetId(id)
+	d.Set("value", token.Token.Value)
+
+	tflog.Info(ctx, "Created service account token successfully")
+
+	return resourceElasticsearchSecurityServiceAccountTokenRead(ctx, d, meta)
+}
+
+// resourceElasticsearchSecurityServiceAccountTokenRead read existing service account token in Elasticsearch
+// The token value can't be read, so it's kept from state
+func resourceElasticsearchSecurityServiceAccountTokenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+	namespace := d.Get("namespace").(string)
+	service := d.Get("service").(string)
+	name := d.Get("name").(string)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.GetServiceCredentials(
+		namespace,
+		service,
+		client.API.Security.GetServiceCredentials.WithContext(ctx),
+		client.API.Security.GetServiceCredentials.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Service account token not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when get service account token %s: %s", id, res.String())
+	}
+
+	credentials := &ServiceAccountCredentials{}
+	if err := json.NewDecoder(res.Body).Decode(credentials); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, ok := credentials.Tokens[name]; !ok {
+		tflog.Warn(ctx, "Service account token not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	tflog.Debug(ctx, "Get service account token successfully", "service_account", credentials.ServiceAccount, "count", credentials.Count)
+
+	return nil
+}
+
+// resourceElasticsearchSecurityServiceAccountTokenDelete delete existing service account token in Elasticsearch
+func resourceElasticsearchSecurityServiceAccountTokenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.DeleteServiceToken(
+		d.Get("name").(string),
+		d.Get("namespace").(string),
+		d.Get("service").(string),
+		client.API.Security.DeleteServiceToken.WithContext(ctx),
+		client.API.Security.DeleteServiceToken.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Service account token not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete service account token %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted service account token successfully")
+	return nil
+}
@@ -0,0 +1,142 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchSecurityServiceAccountToken(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchSecurityServiceAccountTokenDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchSecurityServiceAccountToken,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityServiceAccountTokenExists("elasticsearch_service_account_token.test"),
+					resource.TestCheckResourceAttrSet("elasticsearch_service_account_token.test", "value"),
+				),
+			},
+		},
+	})
+}
+
+func TestServiceAccountTokenDeletePath(t *testing.T) {
+	var path string
+	meta, server := newTestProviderMeta(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Token must be deleted with DELETE method, got %s", r.Method)
+		}
+		path = r.URL.Path
+		w.Write([]byte(`{"found": true}`))
+	})
+	defer server.Close()
+
+	d := resourceElasticsearchSecurityServiceAccountToken().TestResourceData()
+	d.SetId("elastic/fleet-server/terraform-test")
+	d.Set("namespace", "elastic")
+	d.Set("service", "fleet-server")
+	d.Set("name", "terraform-test")
+
+	if diags := resourceElasticsearchSecurityServiceAccountTokenDelete(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("err: %+v", diags)
+	}
+	if path != "/_security/service/elastic/fleet-server/credential/token/terraform-test" {
+		t.Errorf("Token must be deleted on its namespace and service, got %s", path)
+	}
+	if d.Id() != "" {
+		t.Errorf("Token must be removed from state")
+	}
+}
+
+func testCheckElasticsearchSecurityServiceAccountTokenExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No service account token ID is set")
+		}
+
+		found, err := testServiceAccountTokenExist(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return errors.Errorf("Service account token %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchSecurityServiceAccountTokenDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_service_account_token" {
+			continue
+		}
+
+		found, err := testServiceAccountTokenExist(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("Service account token %q still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testServiceAccountTokenExist(id string) (bool, error) {
+	ids := strings.Split(id, "/")
+	if len(ids) != 3 {
+		return false, errors.Errorf("Service account token ID %s must be namespace/service/name", id)
+	}
+
+	meta := testAccProvider.Meta()
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.Security.GetServiceCredentials(
+		ids[0],
+		ids[1],
+		client.API.Security.GetServiceCredentials.WithContext(context.Background()),
+		client.API.Security.GetServiceCredentials.WithPretty(),
+	)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return false, errors.Errorf("Error when get service account token %s: %s", id, res.String())
+	}
+
+	credentials := &ServiceAccountCredentials{}
+	if err := json.NewDecoder(res.Body).Decode(credentials); err != nil {
+		return false, err
+	}
+	_, found := credentials.Tokens[ids[2]]
+
+	return found, nil
+}
+
+var testElasticsearchSecurityServiceAccountToken = `
+resource "elasticsearch_service_account_token" "test" {
+  namespace = "elastic"
+  service   = "fleet-server"
+  name      = "terraform-test"
+}
+`
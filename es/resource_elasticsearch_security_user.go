@@ -3,6 +3,7 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
@@ -10,13 +11,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // User Json object returned by API
@@ -36,13 +36,14 @@ type UserSpec struct {
 // resourceElasticsearchSecurityUser handle the user API call
 func resourceElasticsearchSecurityUser() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchSecurityUserCreate,
-		Read:   resourceElasticsearchSecurityUserRead,
-		Update: resourceElasticsearchSecurityUserUpdate,
-		Delete: resourceElasticsearchSecurityUserDelete,
+		CreateContext: resourceElasticsearchSecurityUserCreate,
+		ReadContext:   resourceElasticsearchSecurityUserRead,
+		UpdateContext: resourceElasticsearchSecurityUserUpdate,
+		DeleteContext: resourceElasticsearchSecurityUserDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -89,61 +90,58 @@ func resourceElasticsearchSecurityUser() *schema.Resource {
 }
 
 // resourceElasticsearchSecurityUserCreate create new user in Elasticsearch
-func resourceElasticsearchSecurityUserCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	username := d.Get("username").(string)
+	ctx = withLogID(ctx, username)
 
-	err := createUser(d, meta, false)
+	err := createUser(ctx, d, meta, false)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(username)
 
-	log.Infof("Created user %s successfully", username)
+	tflog.Info(ctx, "Created user successfully")
 
-	return resourceElasticsearchSecurityUserRead(d, meta)
+	return resourceElasticsearchSecurityUserRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSecurityUserRead read existing user in Elasticsearch
-func resourceElasticsearchSecurityUserRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	log.Debugf("User id:  %s", id)
-
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Security.GetUser(
-		client.API.Security.GetUser.WithContext(context.Background()),
+		client.API.Security.GetUser.WithContext(ctx),
 		client.API.Security.GetUser.WithPretty(),
 		client.API.Security.GetUser.WithUsername(id),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] User %s not found - removing from state", id)
-			log.Warnf("User %s not found - removing from state", id)
+			tflog.Warn(ctx, "User not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get user %s: %s", id, res.String())
+		return diag.Errorf("Error when get user %s: %s", id, res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get user %s successfully:\n%s", id, string(b))
+	tflog.Debug(ctx, "Get user successfully", "body", string(b))
 	user := make(User)
 	err = json.Unmarshal(b, &user)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("User %+v", user)
-
 	d.Set("username", id)
 	d.Set("enabled", user[id].Enabled)
 	d.Set("email", user[id].Email)
@@ -152,19 +150,20 @@ func resourceElasticsearchSecurityUserRead(d *schema.ResourceData, meta interfac
 
 	flattenMetadata, err := convertInterfaceToJsonString(user[id].Metadata)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("metadata", flattenMetadata)
 
-	log.Infof("Read user %s successfully", id)
+	tflog.Info(ctx, "Read user successfully")
 
 	return nil
 }
 
 // resourceElasticsearchSecurityUserUpdate update existing user in Elasticsearch
-func resourceElasticsearchSecurityUserUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
 	// Use change password API if needed
 	if d.HasChange("password") || d.HasChange("password_hash") {
@@ -178,78 +177,77 @@ func resourceElasticsearchSecurityUserUpdate(d *schema.ResourceData, meta interf
 
 		data, err := json.Marshal(payload)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Security.ChangePassword(
 			bytes.NewReader(data),
 			client.API.Security.ChangePassword.WithUsername(id),
-			client.API.Security.ChangePassword.WithContext(context.Background()),
+			client.API.Security.ChangePassword.WithContext(ctx),
 			client.API.Security.ChangePassword.WithPretty(),
 		)
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		defer res.Body.Close()
 
 		if res.IsError() {
-			return errors.Errorf("Error when change password for user %s: %s", id, res.String())
+			return diag.Errorf("Error when change password for user %s: %s", id, res.String())
 		}
 
-		log.Infof("Updated user password %s successfully", d.Id())
+		tflog.Info(ctx, "Updated user password successfully")
 
 	}
 
 	// Use user API for other fiedls
 	if d.HasChange("enabled") || d.HasChange("email") || d.HasChange("full_name") || d.HasChange("roles") || d.HasChange("metadata") {
-		err := createUser(d, meta, true)
+		err := createUser(ctx, d, meta, true)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		log.Infof("Updated user %s successfully", d.Id())
+		tflog.Info(ctx, "Updated user successfully")
 
 	}
 
-	return resourceElasticsearchSecurityUserRead(d, meta)
+	return resourceElasticsearchSecurityUserRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSecurityUserDelete delete existing user in Elasticsearch
-func resourceElasticsearchSecurityUserDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSecurityUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
-	log.Debugf("User id: %s", id)
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Security.DeleteUser(
 		id,
-		client.API.Security.DeleteUser.WithContext(context.Background()),
+		client.API.Security.DeleteUser.WithContext(ctx),
 		client.API.Security.DeleteUser.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] User %s not found - removing from state", id)
-			log.Warnf("User %s not found - removing from state", id)
+			tflog.Warn(ctx, "User not found - removing from state")
 			d.SetId("")
 			return nil
 
 		}
-		return errors.Errorf("Error when delete user %s: %s", id, res.String())
+		return diag.Errorf("Error when delete user %s: %s", id, res.String())
 	}
 
 	d.SetId("")
 
-	log.Infof("Deleted user %s successfully", id)
+	tflog.Info(ctx, "Deleted user successfully")
 	return nil
 
 }
@@ -261,7 +259,7 @@ func (r *UserSpec) String() string {
 }
 
 // createUser create or update user in Elasticsearch
-func createUser(d *schema.ResourceData, meta interface{}, isUpdate bool) error {
+func createUser(ctx context.Context, d *schema.ResourceData, meta interface{}, isUpdate bool) error {
 	username := d.Get("username").(string)
 	enabled := d.Get("enabled").(bool)
 	email := d.Get("email").(string)
@@ -284,19 +282,18 @@ func createUser(d *schema.ResourceData, meta interface{}, isUpdate bool) error {
 		user.PasswordHash = passwordHash
 	}
 
-	log.Debug("Username: ", username)
-	log.Debug("User: ", user)
+	tflog.Debug(ctx, "User", "user", redactBody([]byte(user.String())))
 
 	data, err := json.Marshal(user)
 	if err != nil {
 		return err
 	}
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Security.PutUser(
 		username,
 		bytes.NewReader(data),
-		client.API.Security.PutUser.WithContext(context.Background()),
+		client.API.Security.PutUser.WithContext(ctx),
 		client.API.Security.PutUser.WithPretty(),
 	)
 
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"testing"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -54,7 +53,7 @@ func testCheckElasticsearchSecurityUserExists(name string) resource.TestCheckFun
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Security.GetUser(
 			client.API.Security.GetUser.WithContext(context.Background()),
 			client.API.Security.GetUser.WithPretty(),
@@ -80,7 +79,7 @@ func testCheckElasticsearchSecurityUserDestroy(s *terraform.State) error {
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Security.GetUser(
 			client.API.Security.GetUser.WithContext(context.Background()),
 			client.API.Security.GetUser.WithPretty(),
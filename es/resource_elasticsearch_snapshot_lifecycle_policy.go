@@ -2,6 +2,7 @@
 // API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/slm-api-put.html
 // Supported version:
 //  - v7
+//  - v8
 
 package es
 
@@ -9,13 +10,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // SnapshotLifecyclePolicy object returned by API
@@ -38,13 +38,14 @@ type SnapshotLifecyclePolicyGet struct {
 // resourceElasticsearchSnapshotLifecyclePolicy handle the snapshot lifecycle policy API call
 func resourceElasticsearchSnapshotLifecyclePolicy() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchSnapshotLifecyclePolicyCreate,
-		Read:   resourceElasticsearchSnapshotLifecyclePolicyRead,
-		Update: resourceElasticsearchSnapshotLifecyclePolicyUpdate,
-		Delete: resourceElasticsearchSnapshotLifecyclePolicyDelete,
+		CreateContext: resourceElasticsearchSnapshotLifecyclePolicyCreate,
+		ReadContext:   resourceElasticsearchSnapshotLifecyclePolicyRead,
+		UpdateContext: resourceElasticsearchSnapshotLifecyclePolicyUpdate,
+		DeleteContext: resourceElasticsearchSnapshotLifecyclePolicyDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -80,71 +81,71 @@ func resourceElasticsearchSnapshotLifecyclePolicy() *schema.Resource {
 }
 
 // resourceElasticsearchSnapshotLifecyclePolicyCreate create snapshot lifecycle policy
-func resourceElasticsearchSnapshotLifecyclePolicyCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSnapshotLifecyclePolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
 
-	err := createSnapshotLifecyclePolicy(d, meta)
+	err := createSnapshotLifecyclePolicy(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(name)
-	return resourceElasticsearchSnapshotLifecyclePolicyRead(d, meta)
+	return resourceElasticsearchSnapshotLifecyclePolicyRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSnapshotLifecyclePolicyUpdate update snapshot lifecycle policy
-func resourceElasticsearchSnapshotLifecyclePolicyUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createSnapshotLifecyclePolicy(d, meta)
+func resourceElasticsearchSnapshotLifecyclePolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createSnapshotLifecyclePolicy(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceElasticsearchSnapshotLifecyclePolicyRead(d, meta)
+	return resourceElasticsearchSnapshotLifecyclePolicyRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSnapshotLifecyclePolicyRead read snapshot lifecycle policy
-func resourceElasticsearchSnapshotLifecyclePolicyRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSnapshotLifecyclePolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.SlmGetLifecycle(
-		client.API.SlmGetLifecycle.WithContext(context.Background()),
+		client.API.SlmGetLifecycle.WithContext(ctx),
 		client.API.SlmGetLifecycle.WithPretty(),
 		client.API.SlmGetLifecycle.WithPolicyID(id),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Snapshot lifecycle policy %s not found - removing from state", id)
-			log.Warnf("Snapshot lifecycle policy %s not found - removing from state", id)
+			tflog.Warn(ctx, "Snapshot lifecycle policy not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get snapshot lifecycle policy %s: %s", id, res.String())
+		return diag.Errorf("Error when get snapshot lifecycle policy %s: %s", id, res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get snapshot lifecycle policy successfully:\n%s", string(b))
+	tflog.Debug(ctx, "Get snapshot lifecycle policy successfully", "body", string(b))
 
 	snapshotLifecyclePolicy := make(SnapshotLifecyclePolicy)
 	err = json.Unmarshal(b, &snapshotLifecyclePolicy)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("SnapshotLifecyclePolicy object %+v", snapshotLifecyclePolicy)
-
 	// Manage bug https://github.com/elastic/elasticsearch/issues/47664
 	if len(snapshotLifecyclePolicy) == 0 {
-		fmt.Printf("[WARN] Snapshot lifecycle policy %s not found - removing from state", id)
-		log.Warnf("Snapshot lifecycle policy %s not found - removing from state", id)
+		tflog.Warn(ctx, "Snapshot lifecycle policy not found - removing from state")
 		d.SetId("")
 		return nil
 	}
@@ -156,13 +157,13 @@ func resourceElasticsearchSnapshotLifecyclePolicyRead(d *schema.ResourceData, me
 
 	flattenConfigs, err := convertInterfaceToJsonString(snapshotLifecyclePolicy[id].Policy.Configs)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("configs", flattenConfigs)
 
 	flattenRetention, err := convertInterfaceToJsonString(snapshotLifecyclePolicy[id].Policy.Retention)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("retention", flattenRetention)
 
@@ -170,31 +171,31 @@ func resourceElasticsearchSnapshotLifecyclePolicyRead(d *schema.ResourceData, me
 }
 
 // resourceElasticsearchSnapshotLifecyclePolicyDelete delete snapshot lifecycle policy
-func resourceElasticsearchSnapshotLifecyclePolicyDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSnapshotLifecyclePolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.SlmDeleteLifecycle(
 		id,
-		client.API.SlmDeleteLifecycle.WithContext(context.Background()),
+		client.API.SlmDeleteLifecycle.WithContext(ctx),
 		client.API.SlmDeleteLifecycle.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Snapshot lifecycle policy %s not found - removing from state", id)
-			log.Warnf("Snapshot lifecycle policy %s not found - removing from state", id)
+			tflog.Warn(ctx, "Snapshot lifecycle policy not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when delete snapshot lifecycle policy %s: %s", id, res.String())
+		return diag.Errorf("Error when delete snapshot lifecycle policy %s: %s", id, res.String())
 
 	}
 
@@ -203,7 +204,7 @@ func resourceElasticsearchSnapshotLifecyclePolicyDelete(d *schema.ResourceData,
 }
 
 // createSnapshotLifecyclePolicy permit to create or update snapshot lifecycle policy
-func createSnapshotLifecyclePolicy(d *schema.ResourceData, meta interface{}) error {
+func createSnapshotLifecyclePolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	snapshotName := d.Get("snapshot_name").(string)
 	schedule := d.Get("schedule").(string)
@@ -224,12 +225,12 @@ func createSnapshotLifecyclePolicy(d *schema.ResourceData, meta interface{}) err
 		return err
 	}
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 
 	res, err := client.API.SlmPutLifecycle(
 		name,
 		client.API.SlmPutLifecycle.WithBody(bytes.NewReader(b)),
-		client.API.SlmPutLifecycle.WithContext(context.Background()),
+		client.API.SlmPutLifecycle.WithContext(ctx),
 		client.API.SlmPutLifecycle.WithPretty(),
 	)
 
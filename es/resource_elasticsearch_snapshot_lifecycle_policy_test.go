@@ -7,8 +7,6 @@ import (
 	"io/ioutil"
 	"testing"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -56,7 +54,7 @@ func testCheckElasticsearchSnapshotLifecyclePolicyExists(name string) resource.T
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.SlmGetLifecycle(
 			client.API.SlmGetLifecycle.WithContext(context.Background()),
 			client.API.SlmGetLifecycle.WithPretty(),
@@ -96,7 +94,7 @@ func testCheckElasticsearchSnapshotLifecyclePolicyDestroy(s *terraform.State) er
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.SlmGetLifecycle(
 			client.API.SlmGetLifecycle.WithContext(context.Background()),
 			client.API.SlmGetLifecycle.WithPretty(),
@@ -3,6 +3,7 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
@@ -10,13 +11,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // SnapshotRepository object returned by API
@@ -31,13 +31,14 @@ type SnapshotRepositorySpec struct {
 // resourceElasticsearchSnapshotRepository handle the snapshot repository API call
 func resourceElasticsearchSnapshotRepository() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchSnapshotRepositoryCreate,
-		Read:   resourceElasticsearchSnapshotRepositoryRead,
-		Update: resourceElasticsearchSnapshotRepositoryUpdate,
-		Delete: resourceElasticsearchSnapshotRepositoryDelete,
+		CreateContext: resourceElasticsearchSnapshotRepositoryCreate,
+		ReadContext:   resourceElasticsearchSnapshotRepositoryRead,
+		UpdateContext: resourceElasticsearchSnapshotRepositoryUpdate,
+		DeleteContext: resourceElasticsearchSnapshotRepositoryDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -62,63 +63,66 @@ func resourceElasticsearchSnapshotRepository() *schema.Resource {
 }
 
 // resourceElasticsearchSnapshotRepositoryCreate create snapshot repository
-func resourceElasticsearchSnapshotRepositoryCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSnapshotRepositoryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
 
-	err := createSnapshotRepository(d, meta)
+	err := createSnapshotRepository(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(name)
-	return resourceElasticsearchSnapshotRepositoryRead(d, meta)
+	return resourceElasticsearchSnapshotRepositoryRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSnapshotRepositoryUpdate update the snapshot repository
-func resourceElasticsearchSnapshotRepositoryUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createSnapshotRepository(d, meta)
+func resourceElasticsearchSnapshotRepositoryUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createSnapshotRepository(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceElasticsearchSnapshotRepositoryRead(d, meta)
+	return resourceElasticsearchSnapshotRepositoryRead(ctx, d, meta)
 }
 
 // resourceElasticsearchSnapshotRepositoryRead read the sanpshot repository
-func resourceElasticsearchSnapshotRepositoryRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSnapshotRepositoryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Snapshot.GetRepository(
-		client.API.Snapshot.GetRepository.WithContext(context.Background()),
+		client.API.Snapshot.GetRepository.WithContext(ctx),
 		client.API.Snapshot.GetRepository.WithPretty(),
 		client.API.Snapshot.GetRepository.WithRepository(id),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Snapshot repository %s not found - removing from state", id)
-			log.Warnf("Snapshot repository %s not found - removing from state", id)
+			tflog.Warn(ctx, "Snapshot repository not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get snapshot repository %s: %s", id, res.String())
+		return diag.Errorf("Error when get snapshot repository %s: %s", id, res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get Snapshot repository successfully:\n%s", string(b))
+	tflog.Debug(ctx, "Get Snapshot repository successfully", "body", string(b))
 
 	snapshotRepository := make(SnapshotRepository)
 	err = json.Unmarshal(b, &snapshotRepository)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.Set("name", id)
@@ -129,31 +133,31 @@ func resourceElasticsearchSnapshotRepositoryRead(d *schema.ResourceData, meta in
 }
 
 // resourceElasticsearchSnapshotRepositoryDelete delete the snapshot repository
-func resourceElasticsearchSnapshotRepositoryDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchSnapshotRepositoryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Snapshot.DeleteRepository(
 		[]string{id},
-		client.API.Snapshot.DeleteRepository.WithContext(context.Background()),
+		client.API.Snapshot.DeleteRepository.WithContext(ctx),
 		client.API.Snapshot.DeleteRepository.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Snapshot repository %s not found - removing from state", id)
-			log.Warnf("Snapshot repository %s not found - removing from state", id)
+			tflog.Warn(ctx, "Snapshot repository not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when delete snapshot repository %s: %s", id, res.String())
+		return diag.Errorf("Error when delete snapshot repository %s: %s", id, res.String())
 
 	}
 
@@ -162,7 +166,7 @@ func resourceElasticsearchSnapshotRepositoryDelete(d *schema.ResourceData, meta
 }
 
 // createSnapshotRepository create or update snapshot repository
-func createSnapshotRepository(d *schema.ResourceData, meta interface{}) error {
+func createSnapshotRepository(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	snapshotType := d.Get("type").(string)
 	settings := convertMapInterfaceToMapString(d.Get("settings").(map[string]interface{}))
@@ -177,12 +181,12 @@ func createSnapshotRepository(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 
 	res, err := client.API.Snapshot.CreateRepository(
 		name,
 		bytes.NewReader(b),
-		client.API.Snapshot.CreateRepository.WithContext(context.Background()),
+		client.API.Snapshot.CreateRepository.WithContext(ctx),
 		client.API.Snapshot.CreateRepository.WithPretty(),
 	)
 
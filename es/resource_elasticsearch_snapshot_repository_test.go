@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"testing"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -53,7 +52,7 @@ func testCheckElasticsearchSnapshotRepositoryExists(name string) resource.TestCh
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Snapshot.GetRepository(
 			client.API.Snapshot.GetRepository.WithContext(context.Background()),
 			client.API.Snapshot.GetRepository.WithPretty(),
@@ -79,7 +78,7 @@ func testCheckElasticsearchSnapshotRepositoryDestroy(s *terraform.State) error {
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Snapshot.GetRepository(
 			client.API.Snapshot.GetRepository.WithContext(context.Background()),
 			client.API.Snapshot.GetRepository.WithPretty(),
@@ -0,0 +1,534 @@
+// Manage the transform in elasticsearch
+// The pivot and latest can't be updated, so change them create new transform
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/transform-apis.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// Transforms is the transforms object returned by API
+type Transforms struct {
+	Count      int              `json:"count"`
+	Transforms []*TransformSpec `json:"transforms"`
+}
+
+// TransformSpec is the transform specification
+type TransformSpec struct {
+	ID              string      `json:"id,omitempty"`
+	Description     string      `json:"description,omitempty"`
+	Source          interface{} `json:"source,omitempty"`
+	Dest            interface{} `json:"dest,omitempty"`
+	Pivot           interface{} `json:"pivot,omitempty"`
+	Latest          interface{} `json:"latest,omitempty"`
+	Sync            interface{} `json:"sync,omitempty"`
+	Frequency       string      `json:"frequency,omitempty"`
+	Settings        interface{} `json:"settings,omitempty"`
+	RetentionPolicy interface{} `json:"retention_policy,omitempty"`
+	Metadata        interface{} `json:"_meta,omitempty"`
+}
+
+// TransformsStats is the transforms stats object returned by API
+type TransformsStats struct {
+	Count      int               `json:"count"`
+	Transforms []*TransformStats `json:"transforms"`
+}
+
+// TransformStats is the transform stats, used to know the transform state
+type TransformStats struct {
+	ID            string                  `json:"id"`
+	State         string                  `json:"state"`
+	Checkpointing *TransformCheckpointing `json:"checkpointing,omitempty"`
+}
+
+// TransformCheckpointing is the checkpoints of transform
+type TransformCheckpointing struct {
+	Last *TransformCheckpoint `json:"last,omitempty"`
+}
+
+// TransformCheckpoint is one checkpoint of transform
+type TransformCheckpoint struct {
+	Checkpoint int64 `json:"checkpoint"`
+}
+
+// resourceElasticsearchTransform handle the transform API call
+func resourceElasticsearchTransform() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticsearchTransformCreate,
+		ReadContext:   resourceElasticsearchTransformRead,
+		UpdateContext: resourceElasticsearchTransformUpdate,
+		DeleteContext: resourceElasticsearchTransformDelete,
+
+		Timeouts: defaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceElasticsearchTransformImport,
+		},
+
+		// The sync can be updated, but it can't be added or removed to switch between batch and continuous transform
+		CustomizeDiff: customdiff.ForceNewIfChange("sync", func(ctx context.Context, old, new, meta interface{}) bool {
+			return (old.(string) == "") != (new.(string) == "")
+		}),
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"source": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentTransformSource,
+			},
+			"dest": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"pivot": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{"pivot", "latest"},
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"latest": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{"pivot", "latest"},
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"sync": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"frequency": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"settings": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"retention_policy": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"metadata": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Start or stop the transform. The batch transform stop itself when it finish, so it's not started again",
+			},
+		},
+	}
+}
+
+// resourceElasticsearchTransformCreate create new transform in Elasticsearch and start it if needed
+func resourceElasticsearchTransformCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
+
+	transform := buildTransform(d)
+	transform.Pivot = optionalInterfaceJSON(d.Get("pivot").(string))
+	transform.Latest = optionalInterfaceJSON(d.Get("latest").(string))
+	tflog.Debug(ctx, "Transform", "transform", transform.String())
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.TransformPutTransform(
+		bytes.NewReader([]byte(transform.String())),
+		name,
+		client.API.TransformPutTransform.WithContext(ctx),
+		client.API.TransformPutTransform.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return diag.Errorf("Error when add transform %s: %s", name, res.String())
+	}
+
+	d.SetId(name)
+
+	tflog.Info(ctx, "Created transform successfully")
+
+	if d.Get("enabled").(bool) {
+		if err := startTransform(ctx, name, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceElasticsearchTransformRead(ctx, d, meta)
+}
+
+// resourceElasticsearchTransformRead read existing transform and its state in Elasticsearch
+func resourceElasticsearchTransformRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	transform, err := getTransform(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if transform == nil {
+		tflog.Warn(ctx, "Transform not found - removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	stats, err := getTransformStats(ctx, id, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, "Get transform successfully", "transform", transform.String(), "state", stats.State)
+
+	d.Set("name", id)
+	d.Set("description", transform.Description)
+	d.Set("frequency", transform.Frequency)
+
+	// Batch transform stop itself when it finish, so keep the expected state to not start it again
+	if transform.Sync == nil && isTransformCompleted(stats) {
+		tflog.Debug(ctx, "Batch transform is completed")
+	} else {
+		d.Set("enabled", isTransformRunning(stats.State))
+	}
+
+	jsonFields := map[string]interface{}{
+		"source":           normalizeTransformSource(transform.Source),
+		"dest":             transform.Dest,
+		"pivot":            transform.Pivot,
+		"latest":           transform.Latest,
+		"sync":             transform.Sync,
+		"settings":         transform.Settings,
+		"retention_policy": transform.RetentionPolicy,
+		"metadata":         transform.Metadata,
+	}
+	// Elasticsearch add default values, like the source query. Keep only the keys from state
+	for key, value := range jsonFields {
+		flattenValue, err := filterJSONFromState(d.Get(key).(string), value)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set(key, flattenValue)
+	}
+
+	return nil
+}
+
+// resourceElasticsearchTransformImport import existing transform with all its fields
+func resourceElasticsearchTransformImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ctx = withLogID(ctx, d.Id())
+
+	transform, err := getTransform(ctx, d.Id(), meta)
+	if err != nil {
+		return nil, err
+	}
+	if transform == nil {
+		return nil, errors.Errorf("Transform %s not found", d.Id())
+	}
+
+	jsonFields := map[string]interface{}{
+		"source":           normalizeTransformSource(transform.Source),
+		"dest":             transform.Dest,
+		"pivot":            transform.Pivot,
+		"latest":           transform.Latest,
+		"sync":             transform.Sync,
+		"settings":         transform.Settings,
+		"retention_policy": transform.RetentionPolicy,
+		"metadata":         transform.Metadata,
+	}
+	for key, value := range jsonFields {
+		flattenValue, err := convertInterfaceToJsonString(value)
+		if err != nil {
+			return nil, err
+		}
+		d.Set(key, flattenValue)
+	}
+
+	// The completed batch transform was started
+	if transform.Sync == nil {
+		stats, err := getTransformStats(ctx, d.Id(), meta)
+		if err != nil {
+			return nil, err
+		}
+		d.Set("enabled", isTransformCompleted(stats))
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceElasticsearchTransformUpdate update the updatable fields of existing transform and start or stop it
+func resourceElasticsearchTransformUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	if d.HasChangesExcept("enabled") {
+		transform, err := buildTransformUpdate(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		b, err := json.Marshal(transform)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		tflog.Debug(ctx, "Transform", "transform", string(b))
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.TransformUpdateTransform(
+			bytes.NewReader(b),
+			id,
+			client.API.TransformUpdateTransform.WithContext(ctx),
+			client.API.TransformUpdateTransform.WithPretty(),
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return diag.Errorf("Error when update transform %s: %s", id, res.String())
+		}
+
+		tflog.Info(ctx, "Updated transform successfully")
+	}
+
+	if d.HasChange("enabled") {
+		if d.Get("enabled").(bool) {
+			err := startTransform(ctx, id, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		} else {
+			err := stopTransform(ctx, id, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return resourceElasticsearchTransformRead(ctx, d, meta)
+}
+
+// resourceElasticsearchTransformDelete delete existing transform in Elasticsearch, even if it's running
+func resourceElasticsearchTransformDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Id()
+	ctx = withLogID(ctx, id)
+
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.TransformDeleteTransform(
+		id,
+		client.API.TransformDeleteTransform.WithForce(true),
+		client.API.TransformDeleteTransform.WithContext(ctx),
+		client.API.TransformDeleteTransform.WithPretty(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			tflog.Warn(ctx, "Transform not found - removing from state")
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error when delete transform %s: %s", id, res.String())
+	}
+
+	d.SetId("")
+
+	tflog.Info(ctx, "Deleted transform successfully")
+	return nil
+}
+
+// Print TransformSpec object as Json string
+func (r *TransformSpec) String() string {
+	json, _ := json.Marshal(r)
+	return string(json)
+}
+
+// buildTransform return the transform with only the updatable fields
+func buildTransform(d *schema.ResourceData) *TransformSpec {
+	return &TransformSpec{
+		Description:     d.Get("description").(string),
+		Source:          optionalInterfaceJSON(d.Get("source").(string)),
+		Dest:            optionalInterfaceJSON(d.Get("dest").(string)),
+		Sync:            optionalInterfaceJSON(d.Get("sync").(string)),
+		Frequency:       d.Get("frequency").(string),
+		Settings:        optionalInterfaceJSON(d.Get("settings").(string)),
+		RetentionPolicy: optionalInterfaceJSON(d.Get("retention_policy").(string)),
+		Metadata:        optionalInterfaceJSON(d.Get("metadata").(string)),
+	}
+}
+
+// buildTransformUpdate return the transform to update, with the removed fields explicitly reset.
+// The sync can't be removed, it's handled by CustomizeDiff that recreate the transform.
+func buildTransformUpdate(d *schema.ResourceData) (map[string]interface{}, error) {
+	transform := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(buildTransform(d).String()), &transform); err != nil {
+		return nil, err
+	}
+
+	if d.HasChange("description") && d.Get("description").(string) == "" {
+		transform["description"] = ""
+	}
+	if d.HasChange("retention_policy") && d.Get("retention_policy").(string) == "" {
+		transform["retention_policy"] = nil
+	}
+
+	return transform, nil
+}
+
+// normalizeTransformSource convert the source index set as string to list, like it's returned by API
+func normalizeTransformSource(source interface{}) interface{} {
+	sourceMap, ok := source.(map[string]interface{})
+	if !ok {
+		return source
+	}
+	if index, ok := sourceMap["index"].(string); ok {
+		sourceMap["index"] = []interface{}{index}
+	}
+
+	return sourceMap
+}
+
+// getTransform return the transform from Elasticsearch, or nil if not found
+func getTransform(ctx context.Context, id string, meta interface{}) (*TransformSpec, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.TransformGetTransform(
+		client.API.TransformGetTransform.WithTransformID(id),
+		client.API.TransformGetTransform.WithContext(ctx),
+		client.API.TransformGetTransform.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get transform %s: %s", id, res.String())
+	}
+
+	transforms := &Transforms{}
+	if err := json.NewDecoder(res.Body).Decode(transforms); err != nil {
+		return nil, err
+	}
+	if len(transforms.Transforms) == 0 {
+		return nil, nil
+	}
+
+	return transforms.Transforms[0], nil
+}
+
+// getTransformStats return the current stats of transform, like its state
+func getTransformStats(ctx context.Context, id string, meta interface{}) (*TransformStats, error) {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.TransformGetTransformStats(
+		id,
+		client.API.TransformGetTransformStats.WithContext(ctx),
+		client.API.TransformGetTransformStats.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("Error when get transform stats %s: %s", id, res.String())
+	}
+
+	stats := &TransformsStats{}
+	if err := json.NewDecoder(res.Body).Decode(stats); err != nil {
+		return nil, err
+	}
+	if len(stats.Transforms) == 0 {
+		return nil, errors.Errorf("Error when get transform stats %s: no stats returned", id)
+	}
+
+	return stats.Transforms[0], nil
+}
+
+// isTransformRunning return true if the transform state is a running state
+func isTransformRunning(state string) bool {
+	switch state {
+	case "started", "indexing":
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransformCompleted return true if the transform is stopped after it has processed at least one checkpoint
+// It's the case when batch transform finish
+func isTransformCompleted(stats *TransformStats) bool {
+	return stats.State == "stopped" && stats.Checkpointing != nil && stats.Checkpointing.Last != nil && stats.Checkpointing.Last.Checkpoint > 0
+}
+
+// startTransform start the transform
+func startTransform(ctx context.Context, id string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.TransformStartTransform(
+		id,
+		client.API.TransformStartTransform.WithContext(ctx),
+		client.API.TransformStartTransform.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when start transform %s: %s", id, res.String())
+	}
+
+	tflog.Info(ctx, "Started transform successfully")
+
+	return nil
+}
+
+// stopTransform stop the transform and wait it's stopped
+func stopTransform(ctx context.Context, id string, meta interface{}) error {
+	client := meta.(*ProviderMeta).client
+	res, err := client.API.TransformStopTransform(
+		id,
+		client.API.TransformStopTransform.WithWaitForCompletion(true),
+		client.API.TransformStopTransform.WithContext(ctx),
+		client.API.TransformStopTransform.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when stop transform %s: %s", id, res.String())
+	}
+
+	tflog.Info(ctx, "Stopped transform successfully")
+
+	return nil
+}
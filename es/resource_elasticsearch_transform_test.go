@@ -0,0 +1,334 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchTransform(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchTransformDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchTransform,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchTransformExists("elasticsearch_transform.test"),
+					resource.TestCheckResourceAttr("elasticsearch_transform.test", "enabled", "false"),
+				),
+			},
+			{
+				Config: testElasticsearchTransformUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchTransformExists("elasticsearch_transform.test"),
+					resource.TestCheckResourceAttr("elasticsearch_transform.test", "description", "Updated by Terraform"),
+					resource.TestCheckResourceAttr("elasticsearch_transform.test", "enabled", "true"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_transform.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Elasticsearch add default values when import it, like the source query
+				ImportStateVerifyIgnore: []string{"source", "dest", "settings"},
+			},
+		},
+	})
+}
+
+func TestIsTransformRunning(t *testing.T) {
+	tests := []struct {
+		state    string
+		expected bool
+	}{
+		{"started", true},
+		{"indexing", true},
+		{"stopped", false},
+		{"stopping", false},
+		{"aborting", false},
+		{"failed", false},
+	}
+
+	for _, test := range tests {
+		if result := isTransformRunning(test.state); result != test.expected {
+			t.Errorf("State %s must return %t, got %t", test.state, test.expected, result)
+		}
+	}
+}
+
+func TestIsTransformCompleted(t *testing.T) {
+	tests := []struct {
+		stats    *TransformStats
+		expected bool
+	}{
+		{&TransformStats{State: "stopped", Checkpointing: &TransformCheckpointing{Last: &TransformCheckpoint{Checkpoint: 1}}}, true},
+		{&TransformStats{State: "stopped", Checkpointing: &TransformCheckpointing{Last: &TransformCheckpoint{Checkpoint: 0}}}, false},
+		{&TransformStats{State: "stopped", Checkpointing: &TransformCheckpointing{}}, false},
+		{&TransformStats{State: "stopped"}, false},
+		{&TransformStats{State: "indexing", Checkpointing: &TransformCheckpointing{Last: &TransformCheckpoint{Checkpoint: 1}}}, false},
+	}
+
+	for _, test := range tests {
+		if result := isTransformCompleted(test.stats); result != test.expected {
+			t.Errorf("Stats %+v must return %t, got %t", test.stats, test.expected, result)
+		}
+	}
+}
+
+func TestBuildTransformUpdate(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "terraform-test",
+		Attributes: map[string]string{
+			"description":      "test",
+			"source":           `{"index": "test"}`,
+			"dest":             `{"index": "dest"}`,
+			"retention_policy": `{"time": {"field": "@timestamp", "max_age": "30d"}}`,
+		},
+	}
+	diff := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"description": {
+				Old: "test",
+				New: "",
+			},
+			"retention_policy": {
+				Old: `{"time": {"field": "@timestamp", "max_age": "30d"}}`,
+				New: "",
+			},
+		},
+	}
+	d, err := schema.InternalMap(resourceElasticsearchTransform().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	transform, err := buildTransformUpdate(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if description, ok := transform["description"]; !ok || description != "" {
+		t.Errorf("Removed description must be reset to empty string, got %+v", transform)
+	}
+	if retentionPolicy, ok := transform["retention_policy"]; !ok || retentionPolicy != nil {
+		t.Errorf("Removed retention_policy must be reset to null, got %+v", transform)
+	}
+}
+
+func TestTransformSyncForceNew(t *testing.T) {
+	tests := []struct {
+		old      string
+		new      string
+		forceNew bool
+	}{
+		{"", `{"time": {"field": "@timestamp"}}`, true},
+		{`{"time": {"field": "@timestamp"}}`, "", true},
+		{`{"time": {"field": "@timestamp"}}`, `{"time": {"field": "@timestamp", "delay": "120s"}}`, false},
+	}
+
+	r := resourceElasticsearchTransform()
+	for _, test := range tests {
+		state := &terraform.InstanceState{
+			ID: "terraform-test",
+			Attributes: map[string]string{
+				"name":    "terraform-test",
+				"source":  `{"index": ["test"]}`,
+				"dest":    `{"index": "dest"}`,
+				"pivot":   `{"group_by": {}}`,
+				"sync":    test.old,
+				"enabled": "false",
+			},
+		}
+		config := map[string]interface{}{
+			"name":   "terraform-test",
+			"source": `{"index": "test"}`,
+			"dest":   `{"index": "dest"}`,
+			"pivot":  `{"group_by": {}}`,
+		}
+		if test.new != "" {
+			config["sync"] = test.new
+		}
+
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if diff.RequiresNew() != test.forceNew {
+			t.Errorf("Change sync from %s to %s must force new: %t, got %+v", test.old, test.new, test.forceNew, diff)
+		}
+		if _, ok := diff.Attributes["source"]; ok && !test.forceNew {
+			t.Errorf("Source index set as string must be equivalent to list, got %+v", diff.Attributes["source"])
+		}
+	}
+}
+
+func testCheckElasticsearchTransformExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No transform ID is set")
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.TransformGetTransform(
+			client.API.TransformGetTransform.WithTransformID(rs.Primary.ID),
+			client.API.TransformGetTransform.WithContext(context.Background()),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when get transform %s: %s", rs.Primary.ID, res.String())
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchTransformDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_transform" {
+			continue
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(*ProviderMeta).client
+		res, err := client.API.TransformGetTransform(
+			client.API.TransformGetTransform.WithTransformID(rs.Primary.ID),
+			client.API.TransformGetTransform.WithContext(context.Background()),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if !res.IsError() {
+			return fmt.Errorf("Transform %q still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testElasticsearchTransform = `
+resource "elasticsearch_index" "test_transform" {
+  name     = "terraform-test-transform"
+  mappings = <<EOF
+{
+  "properties": {
+    "@timestamp": { "type": "date" },
+    "customer_id": { "type": "keyword" },
+    "price": { "type": "double" }
+  }
+}
+EOF
+  deletion_protection = false
+}
+
+resource "elasticsearch_transform" "test" {
+  name   = "terraform-test"
+  source = <<EOF
+{
+  "index": ["${elasticsearch_index.test_transform.name}"]
+}
+EOF
+  dest   = <<EOF
+{
+  "index": "terraform-test-transform-dest"
+}
+EOF
+  pivot  = <<EOF
+{
+  "group_by": {
+    "customer_id": {
+      "terms": { "field": "customer_id" }
+    }
+  },
+  "aggregations": {
+    "total_price": {
+      "sum": { "field": "price" }
+    }
+  }
+}
+EOF
+  sync   = <<EOF
+{
+  "time": {
+    "field": "@timestamp",
+    "delay": "60s"
+  }
+}
+EOF
+  frequency = "1m"
+}
+`
+
+var testElasticsearchTransformUpdate = `
+resource "elasticsearch_index" "test_transform" {
+  name     = "terraform-test-transform"
+  mappings = <<EOF
+{
+  "properties": {
+    "@timestamp": { "type": "date" },
+    "customer_id": { "type": "keyword" },
+    "price": { "type": "double" }
+  }
+}
+EOF
+  deletion_protection = false
+}
+
+resource "elasticsearch_transform" "test" {
+  name        = "terraform-test"
+  description = "Updated by Terraform"
+  source      = <<EOF
+{
+  "index": ["${elasticsearch_index.test_transform.name}"]
+}
+EOF
+  dest        = <<EOF
+{
+  "index": "terraform-test-transform-dest"
+}
+EOF
+  pivot       = <<EOF
+{
+  "group_by": {
+    "customer_id": {
+      "terms": { "field": "customer_id" }
+    }
+  },
+  "aggregations": {
+    "total_price": {
+      "sum": { "field": "price" }
+    }
+  }
+}
+EOF
+  sync        = <<EOF
+{
+  "time": {
+    "field": "@timestamp",
+    "delay": "60s"
+  }
+}
+EOF
+  frequency   = "5m"
+  enabled     = true
+}
+`
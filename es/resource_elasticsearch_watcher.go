@@ -3,6 +3,7 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
@@ -10,13 +11,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // Watcher object returned by API
@@ -37,13 +37,14 @@ type WatcherSpec struct {
 // resourceElasticsearchWatcher handle the watcher API call
 func resourceElasticsearchWatcher() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchWatcherCreate,
-		Read:   resourceElasticsearchWatcherRead,
-		Update: resourceElasticsearchWatcherUpdate,
-		Delete: resourceElasticsearchWatcherDelete,
+		CreateContext: resourceElasticsearchWatcherCreate,
+		ReadContext:   resourceElasticsearchWatcherRead,
+		UpdateContext: resourceElasticsearchWatcherUpdate,
+		DeleteContext: resourceElasticsearchWatcherDelete,
 
+		Timeouts: defaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -87,92 +88,89 @@ func resourceElasticsearchWatcher() *schema.Resource {
 }
 
 // resourceElasticsearchWatcherCreate create new watcher in Elasticsearch
-func resourceElasticsearchWatcherCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchWatcherCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
+	ctx = withLogID(ctx, name)
 
-	err := createWatcher(d, meta)
+	err := createWatcher(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(name)
 
-	log.Infof("Created watcher %s successfully", name)
+	tflog.Info(ctx, "Created watcher successfully")
 
-	return resourceElasticsearchWatcherRead(d, meta)
+	return resourceElasticsearchWatcherRead(ctx, d, meta)
 }
 
 // resourceElasticsearchWatcherRead read existing watch in Elasticsearch
-func resourceElasticsearchWatcherRead(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchWatcherRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
+	ctx = withLogID(ctx, id)
 
-	log.Debugf("Watcher id:  %s", id)
-
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Watcher.GetWatch(
 		id,
-		client.API.Watcher.GetWatch.WithContext(context.Background()),
+		client.API.Watcher.GetWatch.WithContext(ctx),
 		client.API.Watcher.GetWatch.WithPretty(),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	defer res.Body.Close()
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Watcher %s not found - removing from state", id)
-			log.Warnf("Watcher %s not found - removing from state", id)
+			tflog.Warn(ctx, "Watcher not found - removing from state")
 			d.SetId("")
 			return nil
 		}
-		return errors.Errorf("Error when get watcher %s: %s", id, res.String())
+		return diag.Errorf("Error when get watcher %s: %s", id, res.String())
 
 	}
 	b, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Debugf("Get watcher %s successfully:\n%s", id, string(b))
+	tflog.Debug(ctx, "Get watcher successfully", "body", string(b))
 	watcher := &Watcher{}
 	err = json.Unmarshal(b, watcher)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	watcherSpec := watcher.Watcher
 
-	log.Debugf("Watcher %+v", watcherSpec)
-
 	d.Set("name", id)
 
 	flattenTrigger, err := convertInterfaceToJsonString(watcherSpec.Trigger)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("trigger", flattenTrigger)
 
 	flattenInput, err := convertInterfaceToJsonString(watcherSpec.Input)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("input", flattenInput)
 
 	flattenCondition, err := convertInterfaceToJsonString(watcherSpec.Condition)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("condition", flattenCondition)
 
 	flattenActions, err := convertInterfaceToJsonString(watcherSpec.Actions)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("actions", flattenActions)
 
 	flattenMetadata, err := convertInterfaceToJsonString(watcherSpec.Metadata)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("metadata", flattenMetadata)
 
@@ -180,56 +178,57 @@ func resourceElasticsearchWatcherRead(d *schema.ResourceData, meta interface{})
 		d.Set("throttle_period", watcherSpec.ThrottlePeriod)
 	}
 
-	log.Infof("Read watcher %s successfully", id)
+	tflog.Info(ctx, "Read watcher successfully")
 
 	return nil
 }
 
 // resourceElasticsearchWatcherUpdate update existing watcher in Elasticsearch
-func resourceElasticsearchWatcherUpdate(d *schema.ResourceData, meta interface{}) error {
-	err := createWatcher(d, meta)
+func resourceElasticsearchWatcherUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = withLogID(ctx, d.Id())
+
+	err := createWatcher(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Infof("Updated watcher %s successfully", d.Id())
+	tflog.Info(ctx, "Updated watcher successfully")
 
-	return resourceElasticsearchWatcherRead(d, meta)
+	return resourceElasticsearchWatcherRead(ctx, d, meta)
 }
 
 // resourceElasticsearchWatcherDelete delete existing watcher in Elasticsearch
-func resourceElasticsearchWatcherDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceElasticsearchWatcherDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	id := d.Id()
-	log.Debugf("Watcher id: %s", id)
+	ctx = withLogID(ctx, id)
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Watcher.DeleteWatch(
 		id,
-		client.API.Watcher.DeleteWatch.WithContext(context.Background()),
+		client.API.Watcher.DeleteWatch.WithContext(ctx),
 		client.API.Watcher.DeleteWatch.WithPretty(),
 	)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			fmt.Printf("[WARN] Watcher %s not found - removing from state", id)
-			log.Warnf("Watcher %s not found - removing from state", id)
+			tflog.Warn(ctx, "Watcher not found - removing from state")
 			d.SetId("")
 			return nil
 
 		}
-		return errors.Errorf("Error when delete watcher %s: %s", id, res.String())
+		return diag.Errorf("Error when delete watcher %s: %s", id, res.String())
 	}
 
 	d.SetId("")
 
-	log.Infof("Deleted watcher %s successfully", id)
+	tflog.Info(ctx, "Deleted watcher successfully")
 	return nil
 
 }
@@ -241,7 +240,7 @@ func (r *WatcherSpec) String() string {
 }
 
 // createWatcher create or update watcher in Elasticsearch
-func createWatcher(d *schema.ResourceData, meta interface{}) error {
+func createWatcher(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	trigger := optionalInterfaceJSON(d.Get("trigger").(string))
 	input := optionalInterfaceJSON(d.Get("input").(string))
@@ -258,19 +257,18 @@ func createWatcher(d *schema.ResourceData, meta interface{}) error {
 		Metadata:       metadata,
 		ThrottlePeriod: throttlePeriod,
 	}
-	log.Debug("Name: ", name)
-	log.Debug("Watcher: ", watcher)
+	tflog.Debug(ctx, "Watcher", "watcher", watcher.String())
 
 	data, err := json.Marshal(watcher)
 	if err != nil {
 		return err
 	}
 
-	client := meta.(*elastic.Client)
+	client := meta.(*ProviderMeta).client
 	res, err := client.API.Watcher.PutWatch(
 		name,
 		client.API.Watcher.PutWatch.WithBody(bytes.NewReader(data)),
-		client.API.Watcher.PutWatch.WithContext(context.Background()),
+		client.API.Watcher.PutWatch.WithContext(ctx),
 		client.API.Watcher.PutWatch.WithPretty(),
 	)
 
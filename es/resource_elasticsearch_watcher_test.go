@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"testing"
 
-	elastic "github.com/elastic/go-elasticsearch/v7"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -53,7 +52,7 @@ func testCheckElasticsearchWatcherExists(name string) resource.TestCheckFunc {
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Watcher.GetWatch(
 			rs.Primary.ID,
 			client.API.Watcher.GetWatch.WithContext(context.Background()),
@@ -79,7 +78,7 @@ func testCheckElasticsearchWatcherDestroy(s *terraform.State) error {
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(*elastic.Client)
+		client := meta.(*ProviderMeta).client
 		res, err := client.API.Watcher.GetWatch(
 			rs.Primary.ID,
 			client.API.Watcher.GetWatch.WithContext(context.Background()),
@@ -0,0 +1,173 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	elastic "github.com/elastic/go-elasticsearch/v7"
+)
+
+const (
+	// retryInitialBackoff is the wait time before the first retry
+	retryInitialBackoff = 1 * time.Second
+
+	// retryMaxBackoff is the maximum wait time between two retries
+	retryMaxBackoff = 30 * time.Second
+)
+
+// defaultRetryOnStatus is the list of HTTP status code that are retried when not set on provider
+var defaultRetryOnStatus = []int{429, 502, 503, 504}
+
+// retryIdempotentMethods is the list of HTTP methods that can be sent again without side effect
+var retryIdempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// retryExcludedPaths is the list of API paths that use idempotent method but create new object on each call
+var retryExcludedPaths = map[string]bool{
+	"/_security/api_key": true,
+}
+
+// noRetryKey is the context key used to disable retry on a request
+type noRetryKey struct{}
+
+// withoutRetry permit to disable the retry on all API calls done with this context
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// retryTransport permit to retry API calls that failed with transient error.
+// Only the idempotent calls are retried, to not create the same object twice.
+type retryTransport struct {
+	transport     http.RoundTripper
+	maxRetries    int
+	retryOnStatus []int
+	backoff       func(attempt int) time.Duration
+}
+
+// newRetryBackoff permit to compute exponential backoff with jitter between each retry.
+// The wait time is picked randomly between the half and the full exponential backoff to avoid that all calls retry at the same time.
+func newRetryBackoff(initial time.Duration, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		backoff := initial
+		for i := 1; i < attempt && backoff < max; i++ {
+			backoff *= 2
+		}
+		if backoff > max {
+			backoff = max
+		}
+
+		half := int64(backoff / 2)
+		return time.Duration(half + rand.Int63n(half+1))
+	}
+}
+
+// setRetryPolicy permit to configure the retry policy used on each API call
+// The retry of the client is disabled because it retry all HTTP methods
+func setRetryPolicy(cfg *elastic.Config, maxRetries int, retryOnStatus []int) {
+	if len(retryOnStatus) == 0 {
+		retryOnStatus = defaultRetryOnStatus
+	}
+
+	transport := cfg.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	cfg.DisableRetry = true
+	cfg.RetryOnStatus = retryOnStatus
+	cfg.Transport = &retryTransport{
+		transport:     transport,
+		maxRetries:    maxRetries,
+		retryOnStatus: retryOnStatus,
+		backoff:       newRetryBackoff(retryInitialBackoff, retryMaxBackoff),
+	}
+}
+
+// isRetryableRequest return true if the request can be sent again without side effect
+func isRetryableRequest(req *http.Request) bool {
+	if noRetry, ok := req.Context().Value(noRetryKey{}).(bool); ok && noRetry {
+		return false
+	}
+	if !retryIdempotentMethods[req.Method] {
+		return false
+	}
+	if retryExcludedPaths[req.URL.Path] {
+		return false
+	}
+
+	return true
+}
+
+// RoundTrip send the request and retry it with backoff when it failed with transient error
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.maxRetries == 0 || !isRetryableRequest(req) {
+		return t.transport.RoundTrip(req)
+	}
+
+	// Body must be sent again on each retry
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if body != nil {
+			r = req.Clone(req.Context())
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		res, err := t.transport.RoundTrip(r)
+		if attempt >= t.maxRetries || !t.shouldRetry(res, err) {
+			return res, err
+		}
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.backoff(attempt + 1)):
+		}
+	}
+}
+
+// shouldRetry return true if the call failed with transient error
+func (t *retryTransport) shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		if err == io.EOF {
+			return true
+		}
+		// Retry on network errors, but not on timeout errors
+		if netErr, ok := err.(net.Error); ok {
+			return !netErr.Timeout()
+		}
+		return false
+	}
+
+	for _, code := range t.retryOnStatus {
+		if res.StatusCode == code {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,242 @@
+package es
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	elastic "github.com/elastic/go-elasticsearch/v7"
+)
+
+var testRetryBody = `{"settings": {"number_of_shards": 1}}`
+
+// newTestRetryServer start HTTP server that failed with status code the number of time asked before to succeed
+func newTestRetryServer(failedStatus int, nbFailed int32, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+
+		// Product check done by the client
+		if r.URL.Path == "/" {
+			w.Write([]byte(`{"version": {"number": "7.16.2", "build_flavor": "default"}, "tagline": "You Know, for Search"}`))
+			return
+		}
+
+		// Body must be sent again on each retry
+		b, _ := ioutil.ReadAll(r.Body)
+		if string(b) != testRetryBody {
+			w.WriteHeader(400)
+			w.Write([]byte(`{"error": "body not received"}`))
+			return
+		}
+
+		if atomic.AddInt32(calls, 1) <= nbFailed {
+			w.WriteHeader(failedStatus)
+			w.Write([]byte(`{"error": "retry"}`))
+			return
+		}
+		w.Write([]byte(`{"acknowledged": true}`))
+	}))
+}
+
+func newTestRetryClient(t *testing.T, url string, maxRetries int, retryOnStatus []int) *elastic.Client {
+	cfg := elastic.Config{
+		Addresses: []string{url},
+	}
+	setRetryPolicy(&cfg, maxRetries, retryOnStatus)
+	// Not wait on test
+	cfg.Transport.(*retryTransport).backoff = newRetryBackoff(time.Millisecond, 5*time.Millisecond)
+
+	client, err := elastic.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return client
+}
+
+func TestRetryOnTransientStatus(t *testing.T) {
+	for _, status := range []int{429, 502, 503, 504} {
+		var calls int32
+		server := newTestRetryServer(status, 2, &calls)
+
+		client := newTestRetryClient(t, server.URL, 3, nil)
+		res, err := client.API.Indices.Create(
+			"test",
+			client.API.Indices.Create.WithBody(strings.NewReader(testRetryBody)),
+			client.API.Indices.Create.WithContext(context.Background()),
+		)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+
+		if res.IsError() {
+			t.Errorf("Status %d must be retried until success, got %s", status, res.String())
+		}
+		if calls != 3 {
+			t.Errorf("Status %d must be called 3 times, got %d", status, calls)
+		}
+
+		server.Close()
+	}
+}
+
+func TestRetryStopAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := newTestRetryServer(503, 10, &calls)
+	defer server.Close()
+
+	client := newTestRetryClient(t, server.URL, 2, nil)
+	res, err := client.API.Indices.Create(
+		"test",
+		client.API.Indices.Create.WithBody(strings.NewReader(testRetryBody)),
+		client.API.Indices.Create.WithContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != 503 {
+		t.Errorf("Last status code must be returned, got %d", res.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("API must be called 3 times, got %d", calls)
+	}
+}
+
+func TestRetryNotOnOtherStatus(t *testing.T) {
+	var calls int32
+	server := newTestRetryServer(400, 1, &calls)
+	defer server.Close()
+
+	client := newTestRetryClient(t, server.URL, 3, nil)
+	res, err := client.API.Indices.Create(
+		"test",
+		client.API.Indices.Create.WithBody(strings.NewReader(testRetryBody)),
+		client.API.Indices.Create.WithContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != 400 {
+		t.Errorf("Status code 400 must not be retried, got %d", res.StatusCode)
+	}
+	if calls != 1 {
+		t.Errorf("API must be called one time, got %d", calls)
+	}
+}
+
+func TestRetryOnCustomStatus(t *testing.T) {
+	var calls int32
+	server := newTestRetryServer(500, 1, &calls)
+	defer server.Close()
+
+	client := newTestRetryClient(t, server.URL, 3, []int{500})
+	res, err := client.API.Indices.Create(
+		"test",
+		client.API.Indices.Create.WithBody(strings.NewReader(testRetryBody)),
+		client.API.Indices.Create.WithContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if res.IsError() {
+		t.Errorf("Status 500 must be retried, got %s", res.String())
+	}
+	if calls != 2 {
+		t.Errorf("API must be called 2 times, got %d", calls)
+	}
+}
+
+func TestRetryNotOnNonIdempotentCall(t *testing.T) {
+	var calls int32
+	server := newTestRetryServer(503, 1, &calls)
+	defer server.Close()
+
+	client := newTestRetryClient(t, server.URL, 3, nil)
+	res, err := client.API.Search(
+		client.API.Search.WithBody(strings.NewReader(testRetryBody)),
+		client.API.Search.WithContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != 503 {
+		t.Errorf("POST call must not be retried, got %d", res.StatusCode)
+	}
+	if calls != 1 {
+		t.Errorf("API must be called one time, got %d", calls)
+	}
+}
+
+func TestRetryNotOnCreateAPIKey(t *testing.T) {
+	var calls int32
+	server := newTestRetryServer(503, 1, &calls)
+	defer server.Close()
+
+	client := newTestRetryClient(t, server.URL, 3, nil)
+	res, err := client.API.Security.CreateAPIKey(
+		strings.NewReader(testRetryBody),
+		client.API.Security.CreateAPIKey.WithContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != 503 {
+		t.Errorf("Create API key must not be retried, got %d", res.StatusCode)
+	}
+	if calls != 1 {
+		t.Errorf("API must be called one time, got %d", calls)
+	}
+}
+
+func TestRetryNotWithoutRetryContext(t *testing.T) {
+	var calls int32
+	server := newTestRetryServer(503, 1, &calls)
+	defer server.Close()
+
+	client := newTestRetryClient(t, server.URL, 3, nil)
+	res, err := client.API.Indices.Create(
+		"test",
+		client.API.Indices.Create.WithBody(strings.NewReader(testRetryBody)),
+		client.API.Indices.Create.WithContext(withoutRetry(context.Background())),
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != 503 {
+		t.Errorf("Call must not be retried when retry is disabled on context, got %d", res.StatusCode)
+	}
+	if calls != 1 {
+		t.Errorf("API must be called one time, got %d", calls)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	backoff := newRetryBackoff(time.Second, 10*time.Second)
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, max := range expected {
+		wait := backoff(i + 1)
+		if wait < max/2 || wait > max {
+			t.Errorf("Backoff for attempt %d must be between %s and %s, got %s", i+1, max/2, max, wait)
+		}
+	}
+}
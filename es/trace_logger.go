@@ -0,0 +1,140 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// redactedValue is the value used in place of secrets
+const redactedValue = "**REDACTED**"
+
+// sensitiveFields is the list of JSON fields that never be logged
+var sensitiveFields = map[string]bool{
+	"password":      true,
+	"password_hash": true,
+	"api_key":       true,
+	"encoded":       true,
+	"signature":     true,
+	"access_token":  true,
+	"refresh_token": true,
+}
+
+// sensitiveNestedFields is the list of JSON fields that never be logged when they are inside the parent field.
+// They are too generic to be always redacted
+var sensitiveNestedFields = map[string]string{
+	"token": "value",
+}
+
+// traceLogger permit to log each API call with secrets redacted
+// It implement the estransport.Logger interface
+type traceLogger struct{}
+
+// LogRoundTrip log the request and response
+func (l *traceLogger) LogRoundTrip(req *http.Request, res *http.Response, err error, start time.Time, dur time.Duration) error {
+	var (
+		reqBody []byte
+		resBody []byte
+		status  int
+	)
+
+	if req != nil && req.Body != nil && req.Body != http.NoBody {
+		reqBody, _ = ioutil.ReadAll(req.Body)
+	}
+	if res != nil {
+		status = res.StatusCode
+		if res.Body != nil && res.Body != http.NoBody {
+			resBody, _ = ioutil.ReadAll(res.Body)
+		}
+	}
+
+	path := req.URL.Path
+	if req.URL.RawQuery != "" {
+		path = fmt.Sprintf("%s?%s", path, req.URL.RawQuery)
+	}
+
+	if err != nil {
+		tflog.Debug(
+			req.Context(),
+			"HTTP trace",
+			"method", req.Method,
+			"path", path,
+			"latency", dur.String(),
+			"error", err.Error(),
+			"request", redactBody(reqBody),
+		)
+		return nil
+	}
+
+	tflog.Debug(
+		req.Context(),
+		"HTTP trace",
+		"method", req.Method,
+		"path", path,
+		"status", status,
+		"latency", dur.String(),
+		"request", redactBody(reqBody),
+		"response", redactBody(resBody),
+	)
+
+	return nil
+}
+
+// RequestBodyEnabled permit to get the request body
+func (l *traceLogger) RequestBodyEnabled() bool { return true }
+
+// ResponseBodyEnabled permit to get the response body
+func (l *traceLogger) ResponseBodyEnabled() bool { return true }
+
+// redactBody permit to remove all secrets from JSON body
+// Body that is not JSON is not logged, because we can't know if it contain secrets
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Sprintf("<not JSON body of %d bytes>", len(body))
+	}
+
+	b, err := json.Marshal(redactValue(data))
+	if err != nil {
+		return fmt.Sprintf("<body of %d bytes>", len(body))
+	}
+
+	return string(b)
+}
+
+// redactValue handle the recursivity to replace secrets on JSON object
+func redactValue(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if sensitiveFields[key] {
+				v[key] = redactedValue
+				continue
+			}
+			if field, ok := sensitiveNestedFields[key]; ok {
+				if nested, ok := value.(map[string]interface{}); ok {
+					if _, ok := nested[field]; ok {
+						nested[field] = redactedValue
+					}
+				}
+			}
+			v[key] = redactValue(value)
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,60 @@
+package es
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+
+	tests := []struct {
+		body     string
+		expected string
+	}{
+		{
+			body:     `{"password": "changeme", "roles": ["superuser"], "full_name": "test"}`,
+			expected: `{"full_name":"test","password":"**REDACTED**","roles":["superuser"]}`,
+		},
+		{
+			body:     `{"password_hash": "$2a$10$xxx"}`,
+			expected: `{"password_hash":"**REDACTED**"}`,
+		},
+		{
+			body:     `{"id": "VuaCfGcBCdbkQm-e5aOx", "name": "test", "api_key": "ui2lp2axTNmsyakw9tvNnw", "encoded": "VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="}`,
+			expected: `{"api_key":"**REDACTED**","encoded":"**REDACTED**","id":"VuaCfGcBCdbkQm-e5aOx","name":"test"}`,
+		},
+		{
+			body:     `{"licenses": [{"uid": "test", "type": "platinum", "signature": "AAAAAwAAAA"}]}`,
+			expected: `{"licenses":[{"signature":"**REDACTED**","type":"platinum","uid":"test"}]}`,
+		},
+		{
+			body:     `{"created": true, "token": {"name": "token1", "value": "AAEAAWVsYXN0aWM"}}`,
+			expected: `{"created":true,"token":{"name":"token1","value":"**REDACTED**"}}`,
+		},
+		{
+			body:     `{"processors": [{"set": {"field": "foo", "value": "bar"}}]}`,
+			expected: `{"processors":[{"set":{"field":"foo","value":"bar"}}]}`,
+		},
+		{
+			body:     `{"settings": {"number_of_shards": 1}}`,
+			expected: `{"settings":{"number_of_shards":1}}`,
+		},
+		{
+			body:     ``,
+			expected: ``,
+		},
+	}
+
+	for _, test := range tests {
+		result := redactBody([]byte(test.body))
+		if result != test.expected {
+			t.Errorf("Body %s must be redacted as %s, got %s", test.body, test.expected, result)
+		}
+	}
+
+	// Body that is not JSON must never be logged
+	result := redactBody([]byte(`password=changeme`))
+	if strings.Contains(result, "changeme") {
+		t.Errorf("Body that is not JSON must not be logged, got %s", result)
+	}
+}
@@ -1,10 +1,20 @@
 package es
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// defaultTimeout is the default timeout to create, update and delete resource
+const defaultTimeout = 5 * time.Minute
+
 // optionalInterfaceJSON permit to convert string as json object
 func optionalInterfaceJSON(input string) interface{} {
 	if input == "" || input == "{}" {
@@ -24,6 +34,16 @@ func convertArrayInterfaceToArrayString(raws []interface{}) []string {
 	return data
 }
 
+// convertArrayInterfaceToArrayInt permit to convert an array of interface to an array of int
+func convertArrayInterfaceToArrayInt(raws []interface{}) []int {
+	data := make([]int, len(raws))
+	for i, raw := range raws {
+		data[i] = raw.(int)
+	}
+
+	return data
+}
+
 func convertMapInterfaceToMapString(raws map[string]interface{}) map[string]string {
 	data := make(map[string]string)
 	for k, v := range raws {
@@ -49,3 +69,132 @@ func convertInterfaceToJsonString(object interface{}) (string, error) {
 
 	return string(b), nil
 }
+
+// withLogID permit to add the resource id on each log message
+// The resource type is already added by the SDK with the `tf_resource_type` field
+func withLogID(ctx context.Context, id string) context.Context {
+	return tflog.With(ctx, "id", id)
+}
+
+// defaultResourceTimeouts permit to get the default timeouts used by resources when no `timeouts` block is set
+func defaultResourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultTimeout),
+		Update: schema.DefaultTimeout(defaultTimeout),
+		Delete: schema.DefaultTimeout(defaultTimeout),
+	}
+}
+
+// flattenSettings permit to convert nested settings on flat settings, like Elasticsearch return with flat_settings.
+// All values are converted to string
+func flattenSettings(prefix string, value interface{}, result map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, subValue := range v {
+			if prefix != "" {
+				key = fmt.Sprintf("%s.%s", prefix, key)
+			}
+			flattenSettings(key, subValue, result)
+		}
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, subValue := range v {
+			values = append(values, settingToString(subValue))
+		}
+		result[prefix] = values
+	case nil:
+		result[prefix] = nil
+	default:
+		result[prefix] = settingToString(v)
+	}
+}
+
+// settingToString convert setting value to string like Elasticsearch
+func settingToString(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// diffSettings return the list of flat settings that are added, changed or removed
+func diffSettings(oldSettings map[string]interface{}, newSettings map[string]interface{}) []string {
+	keys := make([]string, 0)
+	for key, value := range newSettings {
+		if !reflect.DeepEqual(oldSettings[key], value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range oldSettings {
+		if _, ok := newSettings[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// filterJSONFromState return, as JSON string, only the keys from API object that are set on the current JSON string.
+// It permit to not see the default values added by Elasticsearch as diff. Nothing is returned when current JSON is empty
+func filterJSONFromState(current string, remote interface{}) (string, error) {
+	if current == "" {
+		return "", nil
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(current), &data); err != nil {
+		return "", err
+	}
+
+	return convertInterfaceToJsonString(filterInterfaceFromState(data, remote))
+}
+
+// filterInterfaceFromState keep recursively only the map keys from remote that exist on current
+func filterInterfaceFromState(current interface{}, remote interface{}) interface{} {
+	switch c := current.(type) {
+	case map[string]interface{}:
+		r, ok := remote.(map[string]interface{})
+		if !ok {
+			return remote
+		}
+		result := make(map[string]interface{})
+		for key, value := range c {
+			if remoteValue, ok := r[key]; ok {
+				result[key] = filterInterfaceFromState(value, remoteValue)
+			}
+		}
+		return result
+	case []interface{}:
+		r, ok := remote.([]interface{})
+		if !ok || len(r) != len(c) {
+			return remote
+		}
+		result := make([]interface{}, len(r))
+		for i := range r {
+			result[i] = filterInterfaceFromState(c[i], r[i])
+		}
+		return result
+	default:
+		return remote
+	}
+}
+
+// getErrorType return the error type from the body of API error, or empty string if it's not an API error
+func getErrorType(body []byte) string {
+	data := &struct {
+		Error struct {
+			Type string `json:"type"`
+		} `json:"error"`
+	}{}
+	if err := json.Unmarshal(body, data); err != nil {
+		return ""
+	}
+
+	return data.Error.Type
+}
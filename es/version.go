@@ -0,0 +1,81 @@
+package es
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// parseServerVersion permit to parse the version number returned by Elasticsearch
+func parseServerVersion(number string) (*version.Version, error) {
+	v, err := version.NewVersion(number)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when parse Elasticsearch version %s", number)
+	}
+
+	return v, nil
+}
+
+// checkVersion permit to check that Elasticsearch version is between minVersion (included) and maxVersion (excluded)
+// Empty minVersion or maxVersion means no limit
+func checkVersion(current *version.Version, minVersion string, maxVersion string) error {
+	if minVersion != "" && current.LessThan(version.Must(version.NewVersion(minVersion))) {
+		return errors.Errorf("This resource is not supported by Elasticsearch %s, it require Elasticsearch >= %s", current.String(), minVersion)
+	}
+	if maxVersion != "" && current.GreaterThanOrEqual(version.Must(version.NewVersion(maxVersion))) {
+		return errors.Errorf("This resource is not supported by Elasticsearch %s, it require Elasticsearch < %s", current.String(), maxVersion)
+	}
+
+	return nil
+}
+
+// withSupportedVersions permit to declare the Elasticsearch versions supported by a resource.
+// Each call on API is checked against the version detected when the provider is configured.
+func withSupportedVersions(r *schema.Resource, minVersion string, maxVersion string) *schema.Resource {
+	create := r.CreateContext
+	read := r.ReadContext
+	update := r.UpdateContext
+	deleteFunc := r.DeleteContext
+
+	check := func(meta interface{}) error {
+		return checkVersion(meta.(*ProviderMeta).version, minVersion, maxVersion)
+	}
+
+	if create != nil {
+		r.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if err := check(meta); err != nil {
+				return diag.FromErr(err)
+			}
+			return create(ctx, d, meta)
+		}
+	}
+	if read != nil {
+		r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if err := check(meta); err != nil {
+				return diag.FromErr(err)
+			}
+			return read(ctx, d, meta)
+		}
+	}
+	if update != nil {
+		r.UpdateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if err := check(meta); err != nil {
+				return diag.FromErr(err)
+			}
+			return update(ctx, d, meta)
+		}
+	}
+	if deleteFunc != nil {
+		r.DeleteContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if err := check(meta); err != nil {
+				return diag.FromErr(err)
+			}
+			return deleteFunc(ctx, d, meta)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,38 @@
+package es
+
+import (
+	"testing"
+)
+
+func TestCheckVersion(t *testing.T) {
+
+	tests := []struct {
+		current    string
+		minVersion string
+		maxVersion string
+		isError    bool
+	}{
+		{"7.16.2", "7.0.0", "9.0.0", false},
+		{"7.10.0", "7.9.0", "", false},
+		{"7.9.3", "7.10.0", "", true},
+		{"8.0.0", "7.0.0", "9.0.0", false},
+		{"8.1.0-SNAPSHOT", "7.0.0", "9.0.0", false},
+		{"9.0.0", "7.0.0", "9.0.0", true},
+		{"6.8.0", "7.0.0", "", true},
+		{"7.4.0", "", "", false},
+	}
+
+	for _, test := range tests {
+		current, err := parseServerVersion(test.current)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		err = checkVersion(current, test.minVersion, test.maxVersion)
+		if test.isError && err == nil {
+			t.Errorf("Version %s must not be supported with min %s and max %s", test.current, test.minVersion, test.maxVersion)
+		}
+		if !test.isError && err != nil {
+			t.Errorf("Version %s must be supported with min %s and max %s: %s", test.current, test.minVersion, test.maxVersion, err.Error())
+		}
+	}
+}
@@ -8,24 +8,12 @@ package main
 import (
 	"context"
 	"flag"
-	"os"
+	"log"
 
 	"github.com/disaster37/terraform-provider-elasticsearch/v7/es"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
-	log "github.com/sirupsen/logrus"
-	easy "github.com/t-tomalak/logrus-easy-formatter"
 )
 
-func init() {
-
-	log.SetOutput(os.Stderr)
-	log.SetLevel(log.DebugLevel)
-	log.SetFormatter(&easy.Formatter{
-		LogFormat: "[%lvl%] %msg%\n",
-	})
-
-}
-
 func main() {
 
 	var debugMode bool